@@ -0,0 +1,301 @@
+package request
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// bindValues sets the fields of the struct pointed by dst from the
+// values, matching them by the name in the given tag or else the JSON
+// name of the field. Keys without a matching field are an error unless
+// allowUnknown is true.
+func bindValues(values map[string][]string, dst any, tag string, allowUnknown bool) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("dst param must be a pointer to a struct, got %T", dst))
+	}
+	v = v.Elem()
+
+	fields := make(map[string]reflect.Value)
+	collectFields(v, tag, fields)
+
+	for key, vs := range values {
+		field, ok := fields[key]
+		if !ok {
+			if allowUnknown {
+				continue
+			}
+			return &UnknownFieldError{Field: key}
+		}
+		if err := setField(field, key, vs); err != nil {
+			return &TypeError{Field: key}
+		}
+	}
+
+	return nil
+}
+
+// collectFields maps the names of the settable fields of v,
+// including the ones of embedded structs, to their values.
+func collectFields(v reflect.Value, tag string, fields map[string]reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		// Exported fields of embedded structs are promoted,
+		// even if the embedded type is not exported.
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			collectFields(v.Field(i), tag, fields)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = fieldName(f)
+		}
+		fields[name] = v.Field(i)
+	}
+}
+
+// setField converts the values into the type of the field, only slices
+// take more than the first one. Conversion errors name the parameter.
+func setField(field reflect.Value, name string, values []string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(slice.Index(i), Param(value)); err != nil {
+				return withName(name, err)
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	if err := setValue(field, Param(values[0])); err != nil {
+		return withName(name, err)
+	}
+	return nil
+}
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	timeType            = reflect.TypeFor[time.Time]()
+	uuidType            = reflect.TypeFor[UUID]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// convertible reports whether setValue can convert parameters into t.
+func convertible(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType || t == uuidType || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// setValue converts the parameter with the Param converter
+// for the kind of v. Empty values leave v untouched, but strings.
+func setValue(v reflect.Value, p Param) error {
+	if v.Kind() == reflect.Pointer {
+		if p == "" {
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.String && !v.Addr().Type().Implements(textUnmarshalerType) {
+		v.SetString(string(p))
+		return nil
+	}
+	if p == "" {
+		return nil
+	}
+
+	if v.Type() == timeType {
+		t, err := p.Time()
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(p)); err != nil {
+			return p.error("is not valid")
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == durationType {
+			d, err := p.Duration()
+			if err != nil {
+				return err
+			}
+			v.SetInt(int64(d))
+			return nil
+		}
+		i, err := integer[int64](p, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := unsigned[uint64](p, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := float[float64](p, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := p.Bool()
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Array:
+		if v.Type() != uuidType {
+			return p.error(fmt.Sprintf("cannot be converted to %s", v.Type()))
+		}
+		u, err := p.UUID()
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(u))
+	default:
+		return p.error(fmt.Sprintf("cannot be converted to %s", v.Type()))
+	}
+	return nil
+}
+
+// sources are the tags read by Bind, in the order they are looked up.
+var sources = []string{"path", "query", "header", "cookie"}
+
+// lookup returns the values of the parameter with the name from the source.
+func lookup(r *http.Request, source, name string) []string {
+	switch source {
+	case "path":
+		if v := get(r, name); v != "" {
+			return []string{v}
+		}
+	case "query":
+		return r.URL.Query()[name]
+	case "header":
+		return r.Header.Values(name)
+	case "cookie":
+		if c, err := r.Cookie(name); err == nil {
+			return []string{c.Value}
+		}
+	}
+	return nil
+}
+
+// lookupField returns the values of the first source of the field tags
+// which has any. Name is empty if the field has none of the tags.
+func lookupField(r *http.Request, f reflect.StructField) (name string, values []string, required bool) {
+	for _, source := range sources {
+		tag, ok := f.Tag.Lookup(source)
+		if !ok {
+			continue
+		}
+
+		n, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = n
+		}
+		required = required || opts == "required"
+
+		if values = lookup(r, source, n); len(values) > 0 {
+			return n, values, required
+		}
+	}
+	return name, nil, required
+}
+
+// Bind fills the struct pointed by dst from the path, query, header and
+// cookie parameters named by the field tags, e.g. `query:"limit"` or
+// `header:"X-Tenant,required"`. Values are converted like with Param,
+// every conversion error and missing required parameter is returned
+// as FieldErrors.
+func Bind(r *http.Request, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("dst param must be a pointer to a struct, got %T", dst))
+	}
+
+	var errs FieldErrors
+	bindStruct(r, v.Elem(), &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func bindStruct(r *http.Request, v reflect.Value, errs *FieldErrors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		// Exported fields of embedded structs are promoted,
+		// even if the embedded type is not exported.
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			bindStruct(r, v.Field(i), errs)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		name, values, required := lookupField(r, f)
+		if name == "" {
+			continue
+		}
+		if len(values) == 0 {
+			if required {
+				*errs = append(*errs, FieldError{name, "is required"})
+			}
+			continue
+		}
+
+		if err := setField(v.Field(i), name, values); err != nil {
+			msg := err.Error()
+			var paramError *ParamError
+			if errors.As(err, &paramError) {
+				msg = paramError.Reason
+			}
+			*errs = append(*errs, FieldError{name, msg})
+		}
+	}
+}
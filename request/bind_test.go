@@ -0,0 +1,86 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/nukiro/modular/internal/tests"
+)
+
+type pagination struct {
+	Limit int `query:"limit"`
+}
+
+type listArticles struct {
+	pagination
+	ID      int64         `path:"id"`
+	Tags    []string      `query:"tag"`
+	Since   time.Time     `query:"since"`
+	Timeout time.Duration `query:"timeout"`
+	Tenant  string        `header:"X-Tenant,required"`
+	Session *string       `cookie:"session"`
+	Draft   bool          `query:"draft" header:"X-Draft"`
+}
+
+func newBindRequest(target string, params httprouter.Params) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	ctx := context.WithValue(r.Context(), httprouter.ParamsKey, params)
+	return r.WithContext(ctx)
+}
+
+func TestBind(t *testing.T) {
+	t.Run("all sources", func(t *testing.T) {
+		r := newBindRequest("/?limit=5&tag=a&tag=b&since=2024-05-01&timeout=2s", httprouter.Params{{Key: "id", Value: "7"}})
+		r.Header.Set("X-Tenant", "acme")
+		r.Header.Set("X-Draft", "true")
+		r.AddCookie(&http.Cookie{Name: "session", Value: "secret"})
+
+		var got listArticles
+		if err := Bind(r, &got); err != nil {
+			t.Fatalf("got error %q, but want none", err)
+		}
+
+		if got.ID != 7 || got.Limit != 5 || len(got.Tags) != 2 || got.Tenant != "acme" || !got.Draft {
+			t.Errorf("got %+v", got)
+		}
+		if got.Since != time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC) {
+			t.Errorf("got since %v, but want 2024-05-01", got.Since)
+		}
+		if got.Timeout != 2*time.Second {
+			t.Errorf("got timeout %v, but want 2s", got.Timeout)
+		}
+		if got.Session == nil || *got.Session != "secret" {
+			t.Errorf("session cookie was not bound")
+		}
+	})
+
+	t.Run("aggregated errors", func(t *testing.T) {
+		r := newBindRequest("/?limit=five&timeout=soon", httprouter.Params{{Key: "id", Value: "x"}})
+
+		var got listArticles
+		err := Bind(r, &got)
+
+		var errs FieldErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("got error %v, but want field errors", err)
+		}
+
+		want := "limit must be a valid number, id must be a valid number, timeout must be a valid duration, X-Tenant is required"
+		if errs.Error() != want {
+			t.Errorf("got %q, but want %q", errs.Error(), want)
+		}
+	})
+
+	t.Run("non pointer destination", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanic(t, recover(), "Bind", "dst param must be a pointer to a struct, got request.listArticles")
+		}()
+
+		Bind(newBindRequest("/", nil), listArticles{})
+	})
+}
@@ -0,0 +1,70 @@
+package request
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+type contextKey int
+
+const (
+	routeKey contextKey = iota
+	idKey
+	loggerKey
+	optionsKey
+)
+
+// WithRoute prepares the request to hold the route pattern matched by the
+// router. Middlewares running before routing use it to read the pattern
+// once the handler returns.
+func WithRoute(r *http.Request) *http.Request {
+	if _, ok := r.Context().Value(routeKey).(*string); ok {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), routeKey, new(string)))
+}
+
+// SetRoute stores the route pattern matched by the router.
+func SetRoute(r *http.Request, pattern string) *http.Request {
+	r = WithRoute(r)
+	*r.Context().Value(routeKey).(*string) = pattern
+	return r
+}
+
+// Route returns the route pattern matched by the router,
+// or an empty string if the request has not been routed.
+func Route(r *http.Request) string {
+	if route, ok := r.Context().Value(routeKey).(*string); ok {
+		return *route
+	}
+	return ""
+}
+
+// WithID stores the request ID used to correlate logs across services.
+func WithID(r *http.Request, id string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), idKey, id))
+}
+
+// ID returns the request ID, or an empty string if it was not set.
+func ID(r *http.Request) string {
+	id, _ := r.Context().Value(idKey).(string)
+	return id
+}
+
+// WithLogger stores the logger handlers use to log within the request.
+func WithLogger(r *http.Request, logger *slog.Logger) *http.Request {
+	if logger == nil {
+		panic("logger param cannot be nil")
+	}
+	return r.WithContext(context.WithValue(r.Context(), loggerKey, logger))
+}
+
+// Logger returns the request scoped logger set by the server,
+// or the default logger if there is none.
+func Logger(r *http.Request) *slog.Logger {
+	if logger, ok := r.Context().Value(loggerKey).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,119 @@
+package request
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// now is the clock used to check the age of the cursors.
+var now = time.Now
+
+// CursorError is returned when a cursor has been tampered with,
+// it is malformed or it has expired.
+type CursorError struct {
+	Reason string
+}
+
+func (e *CursorError) Error() string {
+	return fmt.Sprintf("cursor parameter %s", e.Reason)
+}
+
+// CursorCodec encodes the sort key values of the last item sent to the
+// client into an opaque token, signed with HMAC-SHA256 so it cannot be
+// forged, and decodes them back when the client asks for the next page.
+type CursorCodec struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewCursorCodec returns a codec signing with the secret. Cursors older
+// than ttl are rejected, a zero ttl means they never expire.
+func NewCursorCodec(secret []byte, ttl time.Duration) *CursorCodec {
+	if len(secret) == 0 {
+		panic("secret param cannot be empty")
+	}
+	return &CursorCodec{secret: secret, ttl: ttl}
+}
+
+type cursorPayload struct {
+	Values json.RawMessage `json:"v"`
+	Issued int64           `json:"t"`
+}
+
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// Encode returns the token for the values, which are encoded as JSON.
+func (c *CursorCodec) Encode(values any) (string, error) {
+	v, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(cursorPayload{Values: v, Issued: now().Unix()})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode verifies the token and decodes its values into dst.
+func (c *CursorCodec) Decode(token string, dst any) error {
+	enc := base64.RawURLEncoding
+
+	p, s, ok := strings.Cut(token, ".")
+	if !ok {
+		return &CursorError{Reason: "is malformed"}
+	}
+	payload, err := enc.DecodeString(p)
+	if err != nil {
+		return &CursorError{Reason: "is malformed"}
+	}
+	signature, err := enc.DecodeString(s)
+	if err != nil {
+		return &CursorError{Reason: "is malformed"}
+	}
+
+	if !hmac.Equal(signature, c.sign(payload)) {
+		return &CursorError{Reason: "is not valid"}
+	}
+
+	var cp cursorPayload
+	if err := json.Unmarshal(payload, &cp); err != nil {
+		return &CursorError{Reason: "is malformed"}
+	}
+	if c.ttl > 0 && now().After(time.Unix(cp.Issued, 0).Add(c.ttl)) {
+		return &CursorError{Reason: "has expired"}
+	}
+
+	if err := json.Unmarshal(cp.Values, dst); err != nil {
+		return &CursorError{Reason: "does not match the expected values"}
+	}
+	return nil
+}
+
+// Cursor decodes the cursor query parameter into dst. It reports
+// false if the client did not send one, asking for the first page.
+// A CursorError can be sent to the client with response.BadRequest.
+func (c *CursorCodec) Cursor(r *http.Request, dst any) (bool, error) {
+	token := r.URL.Query().Get("cursor")
+	if token == "" {
+		return false, nil
+	}
+
+	if err := c.Decode(token, dst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,132 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type lastSeen struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+}
+
+func TestCursorCodec(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"), time.Hour)
+	want := lastSeen{CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), ID: 42}
+
+	token, err := codec.Encode(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ContainsAny(token, "+/=") {
+		t.Errorf("got token %q, but want it url safe", token)
+	}
+
+	var got lastSeen
+	if err := codec.Decode(token, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Errorf("got %+v, but want %+v", got, want)
+	}
+}
+
+func TestCursorCodecRejects(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"), time.Hour)
+	token, err := codec.Encode(lastSeen{ID: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, signature, _ := strings.Cut(token, ".")
+
+	other, err := NewCursorCodec([]byte("other"), time.Hour).Encode(lastSeen{ID: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{"malformed", "not a cursor", "cursor parameter is malformed"},
+		{"bad encoding", payload + ".***", "cursor parameter is malformed"},
+		{"tampered payload", "e30" + payload + "." + signature, "cursor parameter is not valid"},
+		{"other secret", other, "cursor parameter is not valid"},
+	}
+
+	for _, tt := range tokens {
+		t.Run(tt.name, func(t *testing.T) {
+			var got lastSeen
+			err := codec.Decode(tt.token, &got)
+
+			var cursorError *CursorError
+			if !errors.As(err, &cursorError) {
+				t.Fatalf("got error %v, but want a CursorError", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("got error %q, but want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursorCodecExpired(t *testing.T) {
+	issued := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return issued }
+	defer func() { now = time.Now }()
+
+	codec := NewCursorCodec([]byte("secret"), time.Hour)
+	token, err := codec.Encode(lastSeen{ID: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got lastSeen
+	now = func() time.Time { return issued.Add(time.Hour) }
+	if err := codec.Decode(token, &got); err != nil {
+		t.Errorf("got error %v, but want the cursor to be valid", err)
+	}
+
+	now = func() time.Time { return issued.Add(time.Hour + time.Second) }
+	if err := codec.Decode(token, &got); err == nil || err.Error() != "cursor parameter has expired" {
+		t.Errorf("got error %v, but want the cursor to have expired", err)
+	}
+
+	// Cursors never expire without a ttl.
+	codec = NewCursorCodec([]byte("secret"), 0)
+	if err := codec.Decode(token, &got); err != nil {
+		t.Errorf("got error %v, but want the cursor to be valid", err)
+	}
+}
+
+func TestCursor(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"), time.Hour)
+	token, err := codec.Encode(lastSeen{ID: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/articles", nil)
+	var got lastSeen
+	if ok, err := codec.Cursor(r, &got); ok || err != nil {
+		t.Errorf("got %t and error %v, but want the first page", ok, err)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/articles?cursor="+token, nil)
+	if ok, err := codec.Cursor(r, &got); !ok || err != nil {
+		t.Fatalf("got %t and error %v, but want the cursor", ok, err)
+	}
+	if got.ID != 42 {
+		t.Errorf("got id %d, but want 42", got.ID)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/articles?cursor=x"+token, nil)
+	if _, err := codec.Cursor(r, &got); err == nil {
+		t.Error("got no error, but want the tampered cursor to be rejected")
+	}
+}
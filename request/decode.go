@@ -0,0 +1,45 @@
+package request
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// Validator is implemented by types checking themselves after decoding.
+type Validator interface {
+	Validate() error
+}
+
+// Decode reads the request body into a new value of type T, returning
+// the same errors as Read. If T, or a pointer to it, implements
+// Validator, the decoded value is validated before being returned.
+func Decode[T any](w http.ResponseWriter, r *http.Request, opts ...Option) (T, error) {
+	var dst T
+	if err := Read(w, r, &dst, opts...); err != nil {
+		return dst, err
+	}
+
+	if validator := validatorOf(&dst); validator != nil {
+		if err := validator.Validate(); err != nil {
+			return dst, err
+		}
+	}
+
+	return dst, nil
+}
+
+// validatorOf returns the Validator implemented by *dst or dst,
+// or nil if there is none or dst is a nil pointer.
+func validatorOf[T any](dst *T) Validator {
+	if validator, ok := any(dst).(Validator); ok {
+		return validator
+	}
+	if validator, ok := any(*dst).(Validator); ok {
+		v := reflect.ValueOf(*dst)
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return nil
+		}
+		return validator
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type article struct {
+	Title string `json:"title"`
+}
+
+func (a article) Validate() error {
+	if a.Title == "" {
+		return errors.New("title is required")
+	}
+	return nil
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("valid body", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"title": "Go"}`))
+
+		got, err := Decode[article](httptest.NewRecorder(), r)
+		if err != nil {
+			t.Fatalf("got error %q, but want none", err)
+		}
+		if got.Title != "Go" {
+			t.Errorf("got title %q, but want %q", got.Title, "Go")
+		}
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"title": ""}`))
+
+		_, err := Decode[article](httptest.NewRecorder(), r)
+		if err == nil || err.Error() != "title is required" {
+			t.Errorf("got error %v, but want %q", err, "title is required")
+		}
+	})
+
+	t.Run("pointer type", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`null`))
+
+		got, err := Decode[*article](httptest.NewRecorder(), r)
+		if err != nil {
+			t.Fatalf("got error %q, but want none", err)
+		}
+		if got != nil {
+			t.Errorf("got %v, but want nil", got)
+		}
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(""))
+
+		_, err := Decode[article](httptest.NewRecorder(), r)
+		if err == nil || err.Error() != "body must not be empty" {
+			t.Errorf("got error %v, but want %q", err, "body must not be empty")
+		}
+	})
+}
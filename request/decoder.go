@@ -0,0 +1,163 @@
+package request
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Decoder decodes a request body into dst following the options.
+type Decoder func(body io.Reader, dst any, o Options) error
+
+var decoders = struct {
+	sync.RWMutex
+	m map[string]Decoder
+}{
+	m: map[string]Decoder{
+		"application/json":                  decodeJSON,
+		"application/xml":                   decodeXML,
+		"text/xml":                          decodeXML,
+		"application/x-www-form-urlencoded": decodeForm,
+	},
+}
+
+// Register sets the decoder used by Read for the media type, replacing
+// any previous one. It is safe to call it while serving requests.
+// Only JSON, XML and form data are decoded out of the box, other
+// formats such as MessagePack are left for users to plug in here.
+func Register(mediaType string, d Decoder) {
+	if mediaType == "" {
+		panic("media type param cannot be empty")
+	}
+	if d == nil {
+		panic("decoder param cannot be nil")
+	}
+
+	decoders.Lock()
+	defer decoders.Unlock()
+	decoders.m[strings.ToLower(mediaType)] = d
+}
+
+// UnsupportedMediaTypeError is returned when there is no
+// decoder for the Content-Type of the request, or it is not accepted.
+type UnsupportedMediaTypeError struct {
+	MediaType string
+}
+
+func (e *UnsupportedMediaTypeError) Error() string {
+	return fmt.Sprintf("media type %q is not supported", e.MediaType)
+}
+
+// decoderFor returns the decoder for the Content-Type header.
+// Structured syntax suffixes such as application/problem+json
+// fall back to the decoder of the suffix.
+func decoderFor(contentType string, accepted []string) (Decoder, error) {
+	mediaType := "application/json"
+	if contentType != "" {
+		var err error
+		mediaType, _, err = mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, &UnsupportedMediaTypeError{MediaType: contentType}
+		}
+	}
+
+	if len(accepted) > 0 && !slices.Contains(accepted, mediaType) {
+		return nil, &UnsupportedMediaTypeError{MediaType: mediaType}
+	}
+
+	decoders.RLock()
+	defer decoders.RUnlock()
+
+	if d, ok := decoders.m[mediaType]; ok {
+		return d, nil
+	}
+	if _, suffix, ok := strings.Cut(mediaType, "+"); ok {
+		if d, ok := decoders.m["application/"+suffix]; ok {
+			return d, nil
+		}
+	}
+	return nil, &UnsupportedMediaTypeError{MediaType: mediaType}
+}
+
+func decodeXML(body io.Reader, dst any, o Options) error {
+	dec := xml.NewDecoder(body)
+	err := dec.Decode(dst)
+
+	var maxBytesError *http.MaxBytesError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &maxBytesError):
+		return &TooLargeError{Limit: maxBytesError.Limit}
+	case errors.Is(err, io.EOF):
+		if o.AllowEmpty {
+			return nil
+		}
+		return &EmptyBodyError{}
+	default:
+		return &SyntaxError{Format: "XML", Offset: dec.InputOffset()}
+	}
+}
+
+// formStruct returns a pointer to the struct dst points to, following and
+// allocating nested pointers. It reports false if dst does not lead to a
+// struct, such as a map or a slice, which form data cannot be decoded into.
+func formStruct(dst any) (any, bool) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil, false
+	}
+
+	t := v.Type().Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	for v.Elem().Kind() == reflect.Pointer {
+		if v.Elem().IsNil() {
+			v.Elem().Set(reflect.New(v.Elem().Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v.Interface(), true
+}
+
+func decodeForm(body io.Reader, dst any, o Options) error {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return &TooLargeError{Limit: maxBytesError.Limit}
+		}
+		return err
+	}
+
+	if len(b) == 0 {
+		if o.AllowEmpty {
+			return nil
+		}
+		return &EmptyBodyError{}
+	}
+
+	values, err := url.ParseQuery(string(b))
+	if err != nil {
+		return &SyntaxError{Format: "form data"}
+	}
+
+	target, ok := formStruct(dst)
+	if !ok {
+		return &UnsupportedMediaTypeError{MediaType: "application/x-www-form-urlencoded"}
+	}
+	return bindValues(values, target, "form", o.AllowUnknownFields)
+}
@@ -0,0 +1,208 @@
+package request
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nukiro/modular/internal/tests"
+)
+
+type post struct {
+	Title     string   `json:"title" xml:"title" form:"title"`
+	Views     int      `json:"views" xml:"views" form:"views"`
+	Published bool     `json:"published" xml:"published" form:"published"`
+	Tags      []string `json:"tags" xml:"tag" form:"tag"`
+}
+
+func TestReadMediaTypes(t *testing.T) {
+	want := post{"Go", 10, true, []string{"a", "b"}}
+
+	bodies := []struct {
+		contentType string
+		body        string
+	}{
+		{"", `{"title": "Go", "views": 10, "published": true, "tags": ["a", "b"]}`},
+		{"application/json; charset=utf-8", `{"title": "Go", "views": 10, "published": true, "tags": ["a", "b"]}`},
+		{"application/vnd.api+json", `{"title": "Go", "views": 10, "published": true, "tags": ["a", "b"]}`},
+		{"application/xml", `<post><title>Go</title><views>10</views><published>true</published><tag>a</tag><tag>b</tag></post>`},
+		{"application/x-www-form-urlencoded", `title=Go&views=10&published=true&tag=a&tag=b`},
+	}
+
+	for _, tt := range bodies {
+		t.Run(tt.contentType, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+
+			var got post
+			if err := Read(httptest.NewRecorder(), r, &got); err != nil {
+				t.Fatalf("got error %q, but want none", err)
+			}
+			if got.Title != want.Title || got.Views != want.Views || got.Published != want.Published || strings.Join(got.Tags, ",") != "a,b" {
+				t.Errorf("got %+v, but want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestReadUnsupportedMediaType(t *testing.T) {
+	errs := []struct {
+		contentType string
+		opts        []Option
+	}{
+		{"text/plain", nil},
+		{"invalid/", nil},
+		{"application/xml", []Option{Accept("application/json")}},
+	}
+
+	for _, tt := range errs {
+		t.Run(tt.contentType, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+			r.Header.Set("Content-Type", tt.contentType)
+
+			var dst post
+			err := Read(httptest.NewRecorder(), r, &dst, tt.opts...)
+
+			var mediaType *UnsupportedMediaTypeError
+			if !errors.As(err, &mediaType) {
+				t.Errorf("got error %v, but want unsupported media type", err)
+			}
+		})
+	}
+}
+
+func TestReadForm(t *testing.T) {
+	errs := []struct {
+		body   string
+		target any
+	}{
+		{"views=ten", new(*TypeError)},
+		{"author=gopher", new(*UnknownFieldError)},
+		{"", new(*EmptyBodyError)},
+	}
+
+	for _, tt := range errs {
+		t.Run(tt.body, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			var dst post
+			err := Read(httptest.NewRecorder(), r, &dst)
+
+			if !errors.As(err, tt.target) {
+				t.Errorf("got error %v, but want %T", err, tt.target)
+			}
+		})
+	}
+}
+
+func TestReadSyntaxError(t *testing.T) {
+	bodies := []struct {
+		contentType string
+		body        string
+		want        string
+	}{
+		{"application/xml", `<post><title>Go</post>`, "contains badly-formed XML (at character 22)"},
+		{"application/x-www-form-urlencoded", `title=%zz`, "contains badly-formed form data"},
+	}
+
+	for _, tt := range bodies {
+		t.Run(tt.contentType, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+
+			var dst post
+			err := Read(httptest.NewRecorder(), r, &dst)
+
+			var syntax *SyntaxError
+			if !errors.As(err, &syntax) {
+				t.Fatalf("got error %v, but want a syntax error", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("got error %q, but want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("custom decoder", func(t *testing.T) {
+		Register("text/plain", func(body io.Reader, dst any, o Options) error {
+			b, err := io.ReadAll(body)
+			dst.(*post).Title = string(b)
+			return err
+		})
+		defer func() {
+			decoders.Lock()
+			delete(decoders.m, "text/plain")
+			decoders.Unlock()
+		}()
+
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("Go"))
+		r.Header.Set("Content-Type", "text/plain")
+
+		var got post
+		if err := Read(httptest.NewRecorder(), r, &got); err != nil {
+			t.Fatalf("got error %q, but want none", err)
+		}
+		if got.Title != "Go" {
+			t.Errorf("got title %q, but want %q", got.Title, "Go")
+		}
+	})
+
+	t.Run("empty media type", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicEmptyParam(t, recover(), "Register", "media type")
+		}()
+
+		Register("", decodeJSON)
+	})
+
+	t.Run("nil decoder", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "Register", "decoder")
+		}()
+
+		Register("text/plain", nil)
+	})
+}
+
+func TestReadFormDestination(t *testing.T) {
+	form := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("title=Go"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return r
+	}
+
+	t.Run("map", func(t *testing.T) {
+		var dst map[string]any
+		err := Read(httptest.NewRecorder(), form(), &dst)
+
+		var mediaType *UnsupportedMediaTypeError
+		if !errors.As(err, &mediaType) {
+			t.Errorf("got error %v, but want unsupported media type", err)
+		}
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		_, err := Decode[[]post](httptest.NewRecorder(), form())
+
+		var mediaType *UnsupportedMediaTypeError
+		if !errors.As(err, &mediaType) {
+			t.Errorf("got error %v, but want unsupported media type", err)
+		}
+	})
+
+	t.Run("pointer to struct", func(t *testing.T) {
+		dst, err := Decode[*post](httptest.NewRecorder(), form())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dst == nil || dst.Title != "Go" {
+			t.Errorf("got %+v, but want the title to be set", dst)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package request
+
+import (
+	"fmt"
+)
+
+// SyntaxError is returned when the body is not well-formed. Format is
+// the name of the body format, JSON when empty. Offset is the position
+// of the error, or zero if it cannot be known.
+type SyntaxError struct {
+	Format string
+	Offset int64
+}
+
+func (e *SyntaxError) Error() string {
+	format := e.Format
+	if format == "" {
+		format = "JSON"
+	}
+	if e.Offset == 0 {
+		return fmt.Sprintf("contains badly-formed %s", format)
+	}
+	return fmt.Sprintf("contains badly-formed %s (at character %d)", format, e.Offset)
+}
+
+// TypeError is returned when a value does not match the type of the
+// destination field. Field is empty if it cannot be known.
+type TypeError struct {
+	Field  string
+	Offset int64
+}
+
+func (e *TypeError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("contains incorrect JSON type for field: %s", e.Field)
+	}
+	return fmt.Sprintf("contains incorrect JSON type (at character %d)", e.Offset)
+}
+
+// UnknownFieldError is returned when the body contains a key
+// which does not match any field of the destination.
+type UnknownFieldError struct {
+	Field string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("contains unknown key: %s", e.Field)
+}
+
+// TooLargeError is returned when the body is larger than the limit.
+type TooLargeError struct {
+	Limit int64
+}
+
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("must not be larger than %d bytes", e.Limit)
+}
+
+// EmptyBodyError is returned when the body is empty.
+type EmptyBodyError struct{}
+
+func (e *EmptyBodyError) Error() string {
+	return "body must not be empty"
+}
+
+// MultipleValuesError is returned when the body
+// contains more than a single value.
+type MultipleValuesError struct{}
+
+func (e *MultipleValuesError) Error() string {
+	return "must only contain a single JSON value"
+}
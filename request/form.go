@@ -0,0 +1,197 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"slices"
+)
+
+// FormOptions configures how Form reads the request body.
+type FormOptions struct {
+	// Maximum size of the whole body in bytes, 32MB by default.
+	MaxTotalSize int64
+	// Maximum size of each uploaded file in bytes, 10MB by default.
+	MaxFileSize int64
+	// Bytes of the files kept in memory, the rest is stored in temporary files.
+	MaxMemory int64
+	// Media types accepted for the uploaded files, detected from their
+	// content. Any media type is accepted if it is empty.
+	AllowedTypes []string
+}
+
+type FormOption func(*FormOptions)
+
+func DefaultFormOptions() FormOptions {
+	return FormOptions{
+		MaxTotalSize: 32 << 20,
+		MaxFileSize:  10 << 20,
+		MaxMemory:    10 << 20,
+	}
+}
+
+func MaxTotalSize(n int64) FormOption {
+	if n <= 0 {
+		panic("max total size param must be greater than zero")
+	}
+	return func(o *FormOptions) {
+		o.MaxTotalSize = n
+	}
+}
+
+func MaxFileSize(n int64) FormOption {
+	if n <= 0 {
+		panic("max file size param must be greater than zero")
+	}
+	return func(o *FormOptions) {
+		o.MaxFileSize = n
+	}
+}
+
+func MaxMemory(n int64) FormOption {
+	if n < 0 {
+		panic("max memory param cannot be negative")
+	}
+	return func(o *FormOptions) {
+		o.MaxMemory = n
+	}
+}
+
+func AllowFileTypes(mediaTypes ...string) FormOption {
+	return func(o *FormOptions) {
+		o.AllowedTypes = mediaTypes
+	}
+}
+
+// FileTooLargeError is returned when an uploaded file is larger than the limit.
+type FileTooLargeError struct {
+	Field string
+	Limit int64
+}
+
+func (e *FileTooLargeError) Error() string {
+	return fmt.Sprintf("file %s must not be larger than %d bytes", e.Field, e.Limit)
+}
+
+// FileTypeError is returned when the media type
+// of an uploaded file is not allowed.
+type FileTypeError struct {
+	Field     string
+	MediaType string
+}
+
+func (e *FileTypeError) Error() string {
+	return fmt.Sprintf("file %s media type %q is not allowed", e.Field, e.MediaType)
+}
+
+var fileHeaderType = reflect.TypeFor[*multipart.FileHeader]()
+
+// Form reads an application/x-www-form-urlencoded or multipart/form-data
+// body into dst, matching the fields by their form tag. Values are
+// converted like path and query parameters, keys without a matching field
+// are ignored. Uploaded files are set to the *multipart.FileHeader or
+// []*multipart.FileHeader fields after checking their size and type.
+// Files spooled to disk are removed once the request context is done.
+// The body is limited to MaxTotalSize through w, which closes the
+// connection instead of reading the rest of an oversized upload.
+func Form(w http.ResponseWriter, r *http.Request, dst any, opts ...FormOption) error {
+	o := DefaultFormOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	r.Body = http.MaxBytesReader(w, r.Body, o.MaxTotalSize)
+
+	var err error
+	switch mediaType {
+	case "multipart/form-data":
+		err = r.ParseMultipartForm(o.MaxMemory)
+		if r.MultipartForm != nil {
+			context.AfterFunc(r.Context(), func() { r.MultipartForm.RemoveAll() })
+		}
+	case "application/x-www-form-urlencoded":
+		err = r.ParseForm()
+	default:
+		return &UnsupportedMediaTypeError{MediaType: mediaType}
+	}
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return &TooLargeError{Limit: maxBytesError.Limit}
+		}
+		return &SyntaxError{Format: "form data"}
+	}
+
+	if err := bindValues(r.PostForm, dst, "form", true); err != nil {
+		return err
+	}
+	if r.MultipartForm == nil {
+		return nil
+	}
+	return bindFiles(r.MultipartForm.File, dst, o)
+}
+
+// checkFile returns an error if the file does not satisfy the options.
+func checkFile(field string, fh *multipart.FileHeader, o FormOptions) error {
+	if fh.Size > o.MaxFileSize {
+		return &FileTooLargeError{Field: field, Limit: o.MaxFileSize}
+	}
+	if len(o.AllowedTypes) == 0 {
+		return nil
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// DetectContentType considers at most the first 512 bytes.
+	b := make([]byte, 512)
+	n, err := io.ReadFull(f, b)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if n == 0 {
+		return &FileTypeError{Field: field}
+	}
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(b[:n]))
+	if !slices.Contains(o.AllowedTypes, mediaType) {
+		return &FileTypeError{Field: field, MediaType: mediaType}
+	}
+	return nil
+}
+
+func bindFiles(files map[string][]*multipart.FileHeader, dst any, o FormOptions) error {
+	fields := make(map[string]reflect.Value)
+	collectFields(reflect.ValueOf(dst).Elem(), "form", fields)
+
+	for key, fhs := range files {
+		for _, fh := range fhs {
+			if err := checkFile(key, fh, o); err != nil {
+				return err
+			}
+		}
+
+		field, ok := fields[key]
+		if !ok || len(fhs) == 0 {
+			continue
+		}
+		switch {
+		case field.Type() == fileHeaderType:
+			field.Set(reflect.ValueOf(fhs[0]))
+		case field.Kind() == reflect.Slice && field.Type().Elem() == fileHeaderType:
+			field.Set(reflect.ValueOf(fhs))
+		default:
+			return &TypeError{Field: key}
+		}
+	}
+
+	return nil
+}
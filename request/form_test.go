@@ -0,0 +1,168 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type profile struct {
+	Name    string                  `form:"name"`
+	Age     int                     `form:"age"`
+	Avatar  *multipart.FileHeader   `form:"avatar"`
+	Gallery []*multipart.FileHeader `form:"gallery"`
+}
+
+var png = []byte("\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 32))
+
+// multipartRequest builds a request with the given values and files,
+// each file key can be repeated to upload several ones.
+func multipartRequest(t testing.TB, values map[string]string, files map[string][][]byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for key, value := range values {
+		mw.WriteField(key, value)
+	}
+	for key, contents := range files {
+		for _, content := range contents {
+			fw, err := mw.CreateFormFile(key, key+".bin")
+			if err != nil {
+				t.Fatal(err)
+			}
+			fw.Write(content)
+		}
+	}
+	mw.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return r
+}
+
+func TestForm(t *testing.T) {
+	t.Run("url encoded", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=gopher&age=12&csrf=token"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		var got profile
+		if err := Form(httptest.NewRecorder(), r, &got); err != nil {
+			t.Fatalf("got error %q, but want none", err)
+		}
+		if got.Name != "gopher" || got.Age != 12 {
+			t.Errorf("got %+v, but want gopher aged 12", got)
+		}
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		r := multipartRequest(t,
+			map[string]string{"name": "gopher", "age": "12"},
+			map[string][][]byte{"avatar": {png}, "gallery": {png, png}},
+		)
+
+		var got profile
+		if err := Form(httptest.NewRecorder(), r, &got, AllowFileTypes("image/png")); err != nil {
+			t.Fatalf("got error %q, but want none", err)
+		}
+		if got.Name != "gopher" || got.Age != 12 {
+			t.Errorf("got %+v, but want gopher aged 12", got)
+		}
+		if got.Avatar == nil || got.Avatar.Size != int64(len(png)) {
+			t.Errorf("avatar file was not bound")
+		}
+		if len(got.Gallery) != 2 {
+			t.Errorf("got %d gallery files, but want %d", len(got.Gallery), 2)
+		}
+	})
+
+	t.Run("files stored on disk", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		r := multipartRequest(t, nil, map[string][][]byte{"avatar": {png}}).WithContext(ctx)
+
+		var got profile
+		if err := Form(httptest.NewRecorder(), r, &got, MaxMemory(0)); err != nil {
+			t.Fatalf("got error %q, but want none", err)
+		}
+
+		f, err := got.Avatar.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		file, ok := f.(*os.File)
+		if !ok {
+			t.Fatalf("got avatar in memory, but want it in a temporary file")
+		}
+		f.Close()
+
+		// Temporary files are removed once the request is done.
+		cancel()
+		for i := 0; i < 100; i++ {
+			if _, err := os.Stat(file.Name()); errors.Is(err, fs.ErrNotExist) {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Errorf("temporary file %s was not removed", file.Name())
+	})
+
+	errs := []struct {
+		name   string
+		r      func() *http.Request
+		opts   []FormOption
+		target any
+	}{
+		{
+			"type conversion",
+			func() *http.Request { return multipartRequest(t, map[string]string{"age": "twelve"}, nil) },
+			nil,
+			new(*TypeError),
+		},
+		{
+			"file too large",
+			func() *http.Request { return multipartRequest(t, nil, map[string][][]byte{"avatar": {png}}) },
+			[]FormOption{MaxFileSize(8)},
+			new(*FileTooLargeError),
+		},
+		{
+			"file type not allowed",
+			func() *http.Request {
+				return multipartRequest(t, nil, map[string][][]byte{"avatar": {[]byte("plain text")}})
+			},
+			[]FormOption{AllowFileTypes("image/png")},
+			new(*FileTypeError),
+		},
+		{
+			"body too large",
+			func() *http.Request { return multipartRequest(t, nil, map[string][][]byte{"avatar": {png}}) },
+			[]FormOption{MaxTotalSize(16)},
+			new(*TooLargeError),
+		},
+		{
+			"unsupported media type",
+			func() *http.Request { return httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}")) },
+			nil,
+			new(*UnsupportedMediaTypeError),
+		},
+	}
+
+	for _, tt := range errs {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst profile
+			err := Form(httptest.NewRecorder(), tt.r(), &dst, tt.opts...)
+
+			if !errors.As(err, tt.target) {
+				t.Errorf("got error %v, but want %T", err, tt.target)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package request
+
+import (
+	"context"
+	"net/http"
+)
+
+// Options configures how Read decodes the request body.
+type Options struct {
+	// Maximum size of the body in bytes, 1MB by default.
+	MaxBytes int64
+	// Ignore keys which do not match any field of the destination.
+	AllowUnknownFields bool
+	// Decode numbers into interface values as json.Number instead of float64.
+	UseNumber bool
+	// Do not return an error for empty bodies, leaving dst untouched.
+	AllowEmpty bool
+	// Media types accepted by Read, any registered one if empty.
+	MediaTypes []string
+}
+
+type Option func(*Options)
+
+func DefaultOptions() Options {
+	return Options{MaxBytes: 1_048_576}
+}
+
+func MaxBytes(n int64) Option {
+	if n <= 0 {
+		panic("max bytes param must be greater than zero")
+	}
+	return func(o *Options) {
+		o.MaxBytes = n
+	}
+}
+
+func AllowUnknownFields() Option {
+	return func(o *Options) {
+		o.AllowUnknownFields = true
+	}
+}
+
+func UseNumber() Option {
+	return func(o *Options) {
+		o.UseNumber = true
+	}
+}
+
+func AllowEmpty() Option {
+	return func(o *Options) {
+		o.AllowEmpty = true
+	}
+}
+
+// Accept limits the media types of the bodies accepted by Read.
+func Accept(mediaTypes ...string) Option {
+	return func(o *Options) {
+		o.MediaTypes = mediaTypes
+	}
+}
+
+// WithOptions sets the options used by Read for this request,
+// so server wide defaults can be overridden on each call.
+func WithOptions(r *http.Request, o Options) *http.Request {
+	if o.MaxBytes <= 0 {
+		o.MaxBytes = DefaultOptions().MaxBytes
+	}
+	return r.WithContext(context.WithValue(r.Context(), optionsKey, o))
+}
+
+// options returns the request options with the given ones applied.
+func options(r *http.Request, opts []Option) Options {
+	o, ok := r.Context().Value(optionsKey).(Options)
+	if !ok {
+		o = DefaultOptions()
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,87 @@
+package request
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nukiro/modular/internal/tests"
+)
+
+func TestReadOptions(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	}
+
+	t.Run("default options", func(t *testing.T) {
+		var dst article
+		err := Read(httptest.NewRecorder(), newRequest(`{"title": "Go", "author": "gopher"}`), &dst)
+		if err == nil || err.Error() != "contains unknown key: author" {
+			t.Errorf("got error %v, but want unknown key", err)
+		}
+	})
+
+	t.Run("max bytes", func(t *testing.T) {
+		var dst article
+		err := Read(httptest.NewRecorder(), newRequest(`{"title": "Go"}`), &dst, MaxBytes(4))
+		if err == nil || err.Error() != "must not be larger than 4 bytes" {
+			t.Errorf("got error %v, but want too large", err)
+		}
+	})
+
+	t.Run("allow unknown fields", func(t *testing.T) {
+		var dst article
+		err := Read(httptest.NewRecorder(), newRequest(`{"title": "Go", "author": "gopher"}`), &dst, AllowUnknownFields())
+		if err != nil {
+			t.Errorf("got error %q, but want none", err)
+		}
+	})
+
+	t.Run("use number", func(t *testing.T) {
+		var dst map[string]any
+		err := Read(httptest.NewRecorder(), newRequest(`{"id": 12345678901234567890}`), &dst, UseNumber())
+		if err != nil {
+			t.Fatalf("got error %q, but want none", err)
+		}
+		if n, ok := dst["id"].(json.Number); !ok || n.String() != "12345678901234567890" {
+			t.Errorf("got %v, but want a json number", dst["id"])
+		}
+	})
+
+	t.Run("allow empty", func(t *testing.T) {
+		dst := article{Title: "Go"}
+		err := Read(httptest.NewRecorder(), newRequest(""), &dst, AllowEmpty())
+		if err != nil {
+			t.Errorf("got error %q, but want none", err)
+		}
+		if dst.Title != "Go" {
+			t.Errorf("destination was modified")
+		}
+	})
+
+	t.Run("request defaults", func(t *testing.T) {
+		r := WithOptions(newRequest(`{"title": "Go", "author": "gopher"}`), Options{AllowUnknownFields: true})
+
+		var dst article
+		if err := Read(httptest.NewRecorder(), r, &dst); err != nil {
+			t.Errorf("got error %q, but want none", err)
+		}
+
+		// Call options override the request defaults.
+		r = WithOptions(newRequest(`{"title": "Go"}`), Options{AllowUnknownFields: true})
+		err := Read(httptest.NewRecorder(), r, &dst, MaxBytes(4))
+		if err == nil {
+			t.Errorf("Read did not return an error")
+		}
+	})
+
+	t.Run("invalid max bytes", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanic(t, recover(), "MaxBytes", "max bytes param must be greater than zero")
+		}()
+
+		MaxBytes(0)
+	})
+}
@@ -0,0 +1,101 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Page is the slice of a list requested by the client
+// with the limit and offset query parameters.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// PageOptions configures the limit accepted by Pagination.
+type PageOptions struct {
+	// Limit used when the client does not send one, 20 by default.
+	DefaultLimit int
+	// Maximum limit accepted, 100 by default.
+	MaxLimit int
+}
+
+// Pagination reads the limit and offset query parameters. The limit must
+// be between 1 and the maximum, and the offset cannot be negative.
+func Pagination(r *http.Request, o PageOptions) (Page, error) {
+	if o.DefaultLimit <= 0 {
+		o.DefaultLimit = 20
+	}
+	if o.MaxLimit <= 0 {
+		o.MaxLimit = 100
+	}
+
+	params := QueryParams(r, "limit", "offset")
+
+	limit, err := params["limit"].IntOr(o.DefaultLimit)
+	if err != nil {
+		return Page{}, withName("limit", err)
+	}
+	if limit < 1 || limit > o.MaxLimit {
+		return Page{}, &ParamError{Name: "limit", Value: string(params["limit"]), Reason: fmt.Sprintf("must be between 1 and %d", o.MaxLimit)}
+	}
+
+	offset, err := params["offset"].IntOr(0)
+	if err != nil {
+		return Page{}, withName("offset", err)
+	}
+	if offset < 0 {
+		return Page{}, &ParamError{Name: "offset", Value: string(params["offset"]), Reason: "must not be negative"}
+	}
+
+	return Page{Limit: limit, Offset: offset}, nil
+}
+
+// SortField is a field to sort by and its direction.
+type SortField struct {
+	Name string
+	Desc bool
+}
+
+// Sort is the list of fields to sort by, in order of precedence.
+type Sort []SortField
+
+func (s Sort) String() string {
+	fields := make([]string, len(s))
+	for i, f := range s {
+		fields[i] = f.Name
+		if f.Desc {
+			fields[i] = "-" + f.Name
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// Sorting reads the sort query parameter, a comma separated list of fields
+// prefixed by - to sort them in descending order, sort=-created_at,name.
+// Only the allowed fields are accepted.
+func Sorting(r *http.Request, allowed ...string) (Sort, error) {
+	var sort Sort
+	for _, item := range QueryList(r, "sort") {
+		f := SortField{Name: strings.TrimPrefix(string(item), "+")}
+		if name, ok := strings.CutPrefix(string(item), "-"); ok {
+			f = SortField{Name: name, Desc: true}
+		}
+
+		if !slices.Contains(allowed, f.Name) {
+			reason := fmt.Sprintf("field %q is not allowed, use: %s", f.Name, strings.Join(allowed, ", "))
+			return nil, &ParamError{Name: "sort", Value: string(item), Reason: reason}
+		}
+		sort = append(sort, f)
+	}
+
+	return sort, nil
+}
+
+// Filters returns the allowed query parameters sent by the client,
+// to filter a list by their values, ?status=active&tag=go.
+func Filters(r *http.Request, allowed ...string) Params {
+	return QueryParams(r, allowed...)
+}
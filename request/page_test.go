@@ -0,0 +1,90 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPagination(t *testing.T) {
+	pages := []struct {
+		target string
+		want   Page
+		err    string
+	}{
+		{"/", Page{Limit: 20}, ""},
+		{"/?limit=5&offset=10", Page{Limit: 5, Offset: 10}, ""},
+		{"/?limit=0", Page{}, "limit parameter must be between 1 and 50"},
+		{"/?limit=51", Page{}, "limit parameter must be between 1 and 50"},
+		{"/?offset=-1", Page{}, "offset parameter must not be negative"},
+		{"/?offset=first", Page{}, "offset parameter must be a valid number"},
+	}
+
+	for _, tt := range pages {
+		t.Run(tt.target, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+
+			got, err := Pagination(r, PageOptions{MaxLimit: 50})
+
+			if tt.err != "" {
+				var paramError *ParamError
+				if !errors.As(err, &paramError) || err.Error() != tt.err {
+					t.Errorf("got error %v, but want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %q, but want none", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, but want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSorting(t *testing.T) {
+	t.Run("allowed fields", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/?sort=-created_at,name", nil)
+
+		got, err := Sorting(r, "name", "created_at")
+		if err != nil {
+			t.Fatalf("got error %q, but want none", err)
+		}
+
+		want := Sort{{Name: "created_at", Desc: true}, {Name: "name"}}
+		if got.String() != want.String() {
+			t.Errorf("got %v, but want %v", got, want)
+		}
+	})
+
+	t.Run("field not allowed", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/?sort=password", nil)
+
+		_, err := Sorting(r, "name")
+		if err == nil || err.Error() != `sort parameter field "password" is not allowed, use: name` {
+			t.Errorf("got error %v, but want field not allowed", err)
+		}
+	})
+
+	t.Run("without sort", func(t *testing.T) {
+		got, err := Sorting(httptest.NewRequest(http.MethodGet, "/", nil), "name")
+		if err != nil || len(got) != 0 {
+			t.Errorf("got %v %v, but want no fields", got, err)
+		}
+	})
+}
+
+func TestFilters(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/?status=active&password=secret", nil)
+
+	got := Filters(r, "status", "author")
+
+	if len(got) != 1 {
+		t.Fatalf("got %d filters, but want %d", len(got), 1)
+	}
+	if status, _ := got["status"].String(); status != "active" {
+		t.Errorf("got status %q, but want %q", status, "active")
+	}
+}
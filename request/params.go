@@ -1,28 +1,164 @@
 package request
 
 import (
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 )
 
+// ParamError is returned when a parameter cannot be converted. Name is
+// set when the parameter is looked up by name, see Get, so the message
+// can be sent to the client.
+type ParamError struct {
+	Name   string
+	Value  string
+	Reason string
+}
+
+func (e *ParamError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("parameter %s", e.Reason)
+	}
+	return fmt.Sprintf("%s parameter %s", e.Name, e.Reason)
+}
+
+func (p Param) error(reason string) error {
+	return &ParamError{Value: string(p), Reason: reason}
+}
+
+// EmptyParamError is returned when a parameter without value is converted.
+type EmptyParamError struct {
+	Name string
+}
+
+func (e *EmptyParamError) Error() string {
+	if e.Name == "" {
+		return "empty parameter"
+	}
+	return fmt.Sprintf("empty %s parameter", e.Name)
+}
+
+func (p Param) empty() error {
+	return &EmptyParamError{}
+}
+
+// MissingParamError is returned when a required parameter is not present.
+type MissingParamError struct {
+	Name string
+}
+
+func (e *MissingParamError) Error() string {
+	return fmt.Sprintf("missing %s parameter", e.Name)
+}
+
+// withName returns err naming the parameter, if it is a conversion error.
+func withName(name string, err error) error {
+	var paramError *ParamError
+	if errors.As(err, &paramError) {
+		named := *paramError
+		named.Name = name
+		return &named
+	}
+	var emptyError *EmptyParamError
+	if errors.As(err, &emptyError) {
+		return &EmptyParamError{Name: name}
+	}
+	return err
+}
+
 func integer[T int | int8 | int16 | int32 | int64](p Param, size int) (T, error) {
 	if p == "" {
-		return T(0), fmt.Errorf("empty parameter")
+		return T(0), p.empty()
 	}
 
 	i, err := strconv.ParseInt(string(p), 10, size)
 	if err != nil {
-		return T(0), fmt.Errorf("parameter must be a valid number")
+		return T(0), p.error("must be a valid number")
 	}
 
 	return T(i), nil
 }
 
+func unsigned[T uint | uint8 | uint16 | uint32 | uint64](p Param, size int) (T, error) {
+	if p == "" {
+		return T(0), p.empty()
+	}
+
+	u, err := strconv.ParseUint(string(p), 10, size)
+	if err != nil {
+		return T(0), p.error("must be a valid positive number")
+	}
+
+	return T(u), nil
+}
+
+func float[T float32 | float64](p Param, size int) (T, error) {
+	if p == "" {
+		return T(0), p.empty()
+	}
+
+	f, err := strconv.ParseFloat(string(p), size)
+	if err != nil {
+		return T(0), p.error("must be a valid decimal number")
+	}
+
+	return T(f), nil
+}
+
 type Param string
 
+// or returns def when the parameter has no value,
+// otherwise the converted value.
+func or[T any](p Param, def T, convert func() (T, error)) (T, error) {
+	if p == "" {
+		return def, nil
+	}
+	return convert()
+}
+
+func (p Param) IntOr(def int) (int, error) {
+	return or(p, def, p.Int)
+}
+
+func (p Param) Int64Or(def int64) (int64, error) {
+	return or(p, def, p.Int64)
+}
+
+func (p Param) UintOr(def uint) (uint, error) {
+	return or(p, def, p.Uint)
+}
+
+func (p Param) Float64Or(def float64) (float64, error) {
+	return or(p, def, p.Float64)
+}
+
+func (p Param) BoolOr(def bool) (bool, error) {
+	return or(p, def, p.Bool)
+}
+
+func (p Param) StringOr(def string) string {
+	if p == "" {
+		return def
+	}
+	return string(p)
+}
+
+func (p Param) DurationOr(def time.Duration) (time.Duration, error) {
+	return or(p, def, p.Duration)
+}
+
+func (p Param) TimeOr(def time.Time) (time.Time, error) {
+	return or(p, def, p.Time)
+}
+
 func (p Param) Int64() (int64, error) {
 	return integer[int64](p, 64)
 }
@@ -43,15 +179,170 @@ func (p Param) Int() (int, error) {
 	return integer[int](p, 64)
 }
 
+func (p Param) Uint64() (uint64, error) {
+	return unsigned[uint64](p, 64)
+}
+
+func (p Param) Uint32() (uint32, error) {
+	return unsigned[uint32](p, 32)
+}
+
+func (p Param) Uint16() (uint16, error) {
+	return unsigned[uint16](p, 16)
+}
+
+func (p Param) Uint8() (uint8, error) {
+	return unsigned[uint8](p, 8)
+}
+
+func (p Param) Uint() (uint, error) {
+	return unsigned[uint](p, 64)
+}
+
+func (p Param) Float64() (float64, error) {
+	return float[float64](p, 64)
+}
+
+func (p Param) Float32() (float32, error) {
+	return float[float32](p, 32)
+}
+
+func (p Param) Bool() (bool, error) {
+	if p == "" {
+		return false, p.empty()
+	}
+
+	b, err := strconv.ParseBool(string(p))
+	if err != nil {
+		return false, p.error("must be true or false")
+	}
+
+	return b, nil
+}
+
 func (p Param) String() (string, error) {
 	if p == "" {
-		return "", fmt.Errorf("empty parameter")
+		return "", p.empty()
 	}
 	return string(p), nil
 }
 
+// Time parses RFC 3339 timestamps and dates (2006-01-02),
+// which are parsed at midnight UTC.
+func (p Param) Time() (time.Time, error) {
+	if p == "" {
+		return time.Time{}, p.empty()
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		if t, err := time.Parse(layout, string(p)); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, p.error("must be a valid RFC 3339 timestamp or date")
+}
+
+// Duration parses durations such as 300ms, 1.5h or 2h45m.
+func (p Param) Duration() (time.Duration, error) {
+	if p == "" {
+		return 0, p.empty()
+	}
+
+	d, err := time.ParseDuration(string(p))
+	if err != nil {
+		return 0, p.error("must be a valid duration")
+	}
+
+	return d, nil
+}
+
+// Enum returns the value if it is one of the allowed ones.
+func (p Param) Enum(allowed ...string) (string, error) {
+	if p == "" {
+		return "", p.empty()
+	}
+	if !slices.Contains(allowed, string(p)) {
+		return "", p.error(fmt.Sprintf("must be one of: %s", strings.Join(allowed, ", ")))
+	}
+	return string(p), nil
+}
+
+// UUID is a universally unique identifier as defined by RFC 9562.
+type UUID [16]byte
+
+func (u UUID) String() string {
+	b := hex.EncodeToString(u[:])
+	return b[:8] + "-" + b[8:12] + "-" + b[12:16] + "-" + b[16:20] + "-" + b[20:]
+}
+
+// UUID parses identifiers in their canonical
+// form, xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
+func (p Param) UUID() (UUID, error) {
+	var u UUID
+	if p == "" {
+		return u, p.empty()
+	}
+
+	s := string(p)
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, p.error("must be a valid UUID")
+	}
+	s = strings.ReplaceAll(s, "-", "")
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return u, p.error("must be a valid UUID")
+	}
+
+	return u, nil
+}
+
+// As converts the parameter into T, which can be any type whose kind
+// has a Param converter, such as type Status string, or implementing
+// encoding.TextUnmarshaler. It panics for the types which cannot be
+// converted, like structs or slices.
+func As[T any](p Param) (T, error) {
+	var dst T
+	v := reflect.ValueOf(&dst).Elem()
+	if !convertible(v.Type()) {
+		panic(fmt.Sprintf("parameters cannot be converted to %s", v.Type()))
+	}
+
+	if p == "" {
+		return dst, p.empty()
+	}
+	err := setValue(v, p)
+	return dst, err
+}
+
 type Params map[string]Param
 
+// Get converts the named parameter into T like As, its errors name the
+// parameter. It returns a MissingParamError if it was not sent.
+func Get[T any](ps Params, name string) (T, error) {
+	p, ok := ps[name]
+	if !ok {
+		var zero T
+		return zero, &MissingParamError{Name: name}
+	}
+
+	v, err := As[T](p)
+	if err != nil {
+		return v, withName(name, err)
+	}
+	return v, nil
+}
+
+// Required returns a MissingParamError for the
+// first of the names which was not sent.
+func (ps Params) Required(names ...string) error {
+	for _, name := range names {
+		if _, ok := ps[name]; !ok {
+			return &MissingParamError{Name: name}
+		}
+	}
+	return nil
+}
+
 func get(r *http.Request, name string) string {
 	return httprouter.ParamsFromContext(r.Context()).ByName(name)
 }
@@ -64,6 +355,9 @@ func PathParams(r *http.Request, names ...string) Params {
 	return params
 }
 
+// QueryParams returns the first value of the query parameters with the
+// given names. Parameters which were not sent are left out, so Get and
+// Required report them as missing, see QueryValues for repeated ones.
 func QueryParams(r *http.Request, names ...string) Params {
 	params := make(Params)
 	qs := r.URL.Query()
@@ -74,3 +368,28 @@ func QueryParams(r *http.Request, names ...string) Params {
 	}
 	return params
 }
+
+// QueryValues returns every value of a repeated
+// query parameter, ?tag=a&tag=b returns a and b.
+func QueryValues(r *http.Request, name string) []Param {
+	values := r.URL.Query()[name]
+	params := make([]Param, len(values))
+	for i, value := range values {
+		params[i] = Param(value)
+	}
+	return params
+}
+
+// QueryList returns the values of a repeated or comma separated query
+// parameter, ?tag=a,b&tag=c returns a, b and c. Empty items are skipped.
+func QueryList(r *http.Request, name string) []Param {
+	var list []Param
+	for _, value := range r.URL.Query()[name] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, Param(item))
+			}
+		}
+	}
+	return list
+}
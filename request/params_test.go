@@ -0,0 +1,204 @@
+package request
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nukiro/modular/internal/tests"
+)
+
+func TestParamConverters(t *testing.T) {
+	date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	converters := []struct {
+		name    string
+		convert func() (any, error)
+		want    any
+		err     string
+	}{
+		{"int", func() (any, error) { return Param("-12").Int() }, -12, ""},
+		{"int8 overflow", func() (any, error) { return Param("128").Int8() }, int8(0), "parameter must be a valid number"},
+		{"int empty", func() (any, error) { return Param("").Int64() }, int64(0), "empty parameter"},
+		{"uint", func() (any, error) { return Param("12").Uint() }, uint(12), ""},
+		{"uint negative", func() (any, error) { return Param("-1").Uint32() }, uint32(0), "parameter must be a valid positive number"},
+		{"float", func() (any, error) { return Param("1.5").Float64() }, 1.5, ""},
+		{"float invalid", func() (any, error) { return Param("one").Float32() }, float32(0), "parameter must be a valid decimal number"},
+		{"bool", func() (any, error) { return Param("true").Bool() }, true, ""},
+		{"bool invalid", func() (any, error) { return Param("yes").Bool() }, false, "parameter must be true or false"},
+		{"time", func() (any, error) { return Param("2024-05-01T00:00:00Z").Time() }, date, ""},
+		{"date", func() (any, error) { return Param("2024-05-01").Time() }, date, ""},
+		{"time invalid", func() (any, error) { return Param("01/05/2024").Time() }, time.Time{}, "parameter must be a valid RFC 3339 timestamp or date"},
+		{"duration", func() (any, error) { return Param("1h30m").Duration() }, 90 * time.Minute, ""},
+		{"duration invalid", func() (any, error) { return Param("90").Duration() }, time.Duration(0), "parameter must be a valid duration"},
+		{"enum", func() (any, error) { return Param("asc").Enum("asc", "desc") }, "asc", ""},
+		{"enum invalid", func() (any, error) { return Param("up").Enum("asc", "desc") }, "", "parameter must be one of: asc, desc"},
+		{"uuid invalid", func() (any, error) { return Param("123e4567e89b12d3a456426614174000").UUID() }, UUID{}, "parameter must be a valid UUID"},
+		{"string empty", func() (any, error) { return Param("").String() }, "", "empty parameter"},
+	}
+
+	for _, tt := range converters {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.convert()
+
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("got error %v, but want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %q, but want none", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, but want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("uuid", func(t *testing.T) {
+		want := "123e4567-e89b-12d3-a456-426614174000"
+		u, err := Param(want).UUID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.String() != want {
+			t.Errorf("got %s, but want %s", u, want)
+		}
+	})
+}
+
+func TestAs(t *testing.T) {
+	t.Run("converter", func(t *testing.T) {
+		got, err := As[uint16](Param("20"))
+		if err != nil || got != 20 {
+			t.Errorf("got %v %v, but want 20", got, err)
+		}
+	})
+
+	t.Run("text unmarshaler", func(t *testing.T) {
+		got, err := As[net.IP](Param("127.0.0.1"))
+		if err != nil || got.String() != "127.0.0.1" {
+			t.Errorf("got %v %v, but want 127.0.0.1", got, err)
+		}
+	})
+
+	t.Run("named types", func(t *testing.T) {
+		type Status string
+		type Level int
+
+		status, err := As[Status](Param("active"))
+		if err != nil || status != "active" {
+			t.Errorf("got %v %v, but want active", status, err)
+		}
+		level, err := As[Level](Param("3"))
+		if err != nil || level != 3 {
+			t.Errorf("got %v %v, but want 3", level, err)
+		}
+		if _, err := As[Level](Param("high")); err == nil || err.Error() != "parameter must be a valid number" {
+			t.Errorf("got error %v, but want a number error", err)
+		}
+	})
+
+	t.Run("type cannot be converted", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanic(t, recover(), "As", "parameters cannot be converted to []int")
+		}()
+
+		As[[]int](Param("1"))
+	})
+
+	t.Run("conversion error", func(t *testing.T) {
+		_, err := As[time.Duration](Param("soon"))
+		var paramError *ParamError
+		if !errors.As(err, &paramError) || err.Error() != "parameter must be a valid duration" {
+			t.Errorf("got error %v, but want a duration error", err)
+		}
+	})
+}
+
+func TestGet(t *testing.T) {
+	params := Params{"timeout": "soon", "limit": "20", "page": ""}
+
+	lookups := []struct {
+		name string
+		err  string
+	}{
+		{"timeout", "timeout parameter must be a valid duration"},
+		{"page", "empty page parameter"},
+		{"sort", "missing sort parameter"},
+	}
+
+	for _, tt := range lookups {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Get[time.Duration](params, tt.name)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("got error %v, but want %q", err, tt.err)
+			}
+		})
+	}
+
+	t.Run("converted value", func(t *testing.T) {
+		got, err := Get[int](params, "limit")
+		if err != nil || got != 20 {
+			t.Errorf("got %v %v, but want 20", got, err)
+		}
+	})
+}
+
+func TestQueryParams(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/?tag=a,b&tag=c&limit=&page=2", nil)
+	params := QueryParams(r, "tag", "limit", "page", "sort")
+
+	t.Run("repeated values", func(t *testing.T) {
+		if got := QueryValues(r, "tag"); !slices.Equal(got, []Param{"a,b", "c"}) {
+			t.Errorf("got values %v, but want [a,b c]", got)
+		}
+		if got := QueryList(r, "tag"); !slices.Equal(got, []Param{"a", "b", "c"}) {
+			t.Errorf("got list %v, but want [a b c]", got)
+		}
+		if got, _ := params["tag"].String(); got != "a,b" {
+			t.Errorf("got first value %q, but want %q", got, "a,b")
+		}
+	})
+
+	t.Run("default values", func(t *testing.T) {
+		limit, err := params["limit"].IntOr(10)
+		if err != nil || limit != 10 {
+			t.Errorf("got limit %d %v, but want 10", limit, err)
+		}
+		page, err := params["page"].IntOr(1)
+		if err != nil || page != 2 {
+			t.Errorf("got page %d %v, but want 2", page, err)
+		}
+		if sort := params["sort"].StringOr("id"); sort != "id" {
+			t.Errorf("got sort %q, but want %q", sort, "id")
+		}
+	})
+
+	t.Run("missing parameters", func(t *testing.T) {
+		if _, ok := params["limit"]; !ok {
+			t.Errorf("empty parameter was not present")
+		}
+		if _, ok := params["sort"]; ok {
+			t.Errorf("missing parameter was present")
+		}
+
+		_, err := Get[string](params, "sort")
+		var missing *MissingParamError
+		if !errors.As(err, &missing) || err.Error() != "missing sort parameter" {
+			t.Errorf("got error %v, but want missing sort parameter", err)
+		}
+
+		if err := params.Required("tag", "sort"); err == nil || err.Error() != "missing sort parameter" {
+			t.Errorf("got error %v, but want missing sort parameter", err)
+		}
+		if err := params.Required("tag", "limit"); err != nil {
+			t.Errorf("got error %q, but want none", err)
+		}
+	})
+}
@@ -3,19 +3,37 @@ package request
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"net/http"
 	"strings"
 )
 
-func Read(w http.ResponseWriter, r *http.Request, dst any) error {
-	// Limit the size of the request body to 1MB.
-	maxBytes := 1_048_576
-	r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytes))
+// Read decodes the request body into dst with the decoder registered
+// for its Content-Type, JSON if the header is missing. By default the
+// body is limited to 1MB and unknown keys are not allowed, options passed
+// to the server or to each call change this behaviour.
+func Read(w http.ResponseWriter, r *http.Request, dst any, opts ...Option) error {
+	o := options(r, opts)
+
+	decode, err := decoderFor(r.Header.Get("Content-Type"), o.MediaTypes)
+	if err != nil {
+		return err
+	}
+
+	// Limit the size of the request body.
+	r.Body = http.MaxBytesReader(w, r.Body, o.MaxBytes)
 	// Decode the request body into the target destination
-	dec := json.NewDecoder(r.Body)
-	dec.DisallowUnknownFields()
+	return decode(r.Body, dst, o)
+}
+
+func decodeJSON(body io.Reader, dst any, o Options) error {
+	dec := json.NewDecoder(body)
+	if !o.AllowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if o.UseNumber {
+		dec.UseNumber()
+	}
 
 	err := dec.Decode(dst)
 
@@ -29,26 +47,29 @@ func Read(w http.ResponseWriter, r *http.Request, dst any) error {
 
 		switch {
 		case errors.As(err, &syntaxError):
-			return fmt.Errorf("contains badly-formed JSON (at character %d)", syntaxError.Offset)
+			return &SyntaxError{Offset: syntaxError.Offset}
 
 		case errors.Is(err, io.ErrUnexpectedEOF):
-			return errors.New("contains badly-formed JSON")
+			return &SyntaxError{}
 
 		case errors.As(err, &unmarshallTypeError):
-			if unmarshallTypeError.Field != "" {
-				return fmt.Errorf("contains incorrect JSON type for field: %s", strings.ReplaceAll(unmarshallTypeError.Field, "\"", ""))
+			return &TypeError{
+				Field:  strings.ReplaceAll(unmarshallTypeError.Field, "\"", ""),
+				Offset: unmarshallTypeError.Offset,
 			}
-			return fmt.Errorf("contains incorrect JSON type (at character %d)", unmarshallTypeError.Offset)
 
 		case errors.Is(err, io.EOF):
-			return errors.New("body must not be empty")
+			if o.AllowEmpty {
+				return nil
+			}
+			return &EmptyBodyError{}
 
 		case strings.HasPrefix(err.Error(), "json: unknown field "):
 			fieldName := strings.ReplaceAll(strings.TrimPrefix(err.Error(), "json: unknown field "), "\"", "")
-			return fmt.Errorf("contains unknown key: %s", fieldName)
+			return &UnknownFieldError{Field: fieldName}
 
 		case errors.As(err, &maxBytesError):
-			return fmt.Errorf("must not be larger than %d bytes", maxBytesError.Limit)
+			return &TooLargeError{Limit: maxBytesError.Limit}
 
 		case errors.As(err, &invalidUnmarshalError):
 			panic(err)
@@ -65,7 +86,7 @@ func Read(w http.ResponseWriter, r *http.Request, dst any) error {
 	// message.
 	err = dec.Decode(&struct{}{})
 	if !errors.Is(err, io.EOF) {
-		return errors.New("must only contain a single JSON value")
+		return &MultipleValuesError{}
 	}
 
 	return nil
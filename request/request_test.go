@@ -0,0 +1,53 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nukiro/modular/internal/tests"
+)
+
+func TestRead(t *testing.T) {
+	errs := []struct {
+		body   string
+		target any
+		msg    string
+	}{
+		{`{"title": "Go",}`, new(*SyntaxError), "contains badly-formed JSON (at character 16)"},
+		{`{"title": "Go"`, new(*SyntaxError), "contains badly-formed JSON"},
+		{`{"title": 1}`, new(*TypeError), "contains incorrect JSON type for field: title"},
+		{`["Go"]`, new(*TypeError), "contains incorrect JSON type (at character 1)"},
+		{`{"author": "gopher"}`, new(*UnknownFieldError), "contains unknown key: author"},
+		{``, new(*EmptyBodyError), "body must not be empty"},
+		{`{"title": "Go"}{}`, new(*MultipleValuesError), "must only contain a single JSON value"},
+		{`{"title": "` + strings.Repeat("a", 1_048_576) + `"}`, new(*TooLargeError), "must not be larger than 1048576 bytes"},
+	}
+
+	for _, tt := range errs {
+		t.Run(tt.msg, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			var dst article
+			err := Read(httptest.NewRecorder(), r, &dst)
+
+			if !errors.As(err, tt.target) {
+				t.Fatalf("got error %T, but want %T", err, tt.target)
+			}
+			if err.Error() != tt.msg {
+				t.Errorf("got %q, but want %q", err.Error(), tt.msg)
+			}
+		})
+	}
+
+	t.Run("non pointer destination", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanic(t, recover(), "Read", "json: Unmarshal(non-pointer request.article)")
+		}()
+
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		Read(httptest.NewRecorder(), r, article{})
+	})
+}
@@ -0,0 +1,283 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// PartsOptions configures how Parts reads a multipart body.
+type PartsOptions struct {
+	// Maximum size of each part in bytes, 10MB by default.
+	MaxPartSize int64
+	// Parts larger than the threshold are spooled to a temporary file,
+	// smaller ones are kept in memory. 1MB by default.
+	MemoryThreshold int64
+	// Directory of the temporary files, os.TempDir if empty.
+	TempDir string
+	// Maximum size of the whole body in bytes, 32MB by default.
+	MaxPartsSize int64
+	// Maximum number of parts, 100 by default.
+	MaxParts int
+}
+
+type PartsOption func(*PartsOptions)
+
+func DefaultPartsOptions() PartsOptions {
+	return PartsOptions{
+		MaxPartSize:     10 << 20,
+		MemoryThreshold: 1 << 20,
+		MaxPartsSize:    32 << 20,
+		MaxParts:        100,
+	}
+}
+
+func MaxPartSize(n int64) PartsOption {
+	if n <= 0 {
+		panic("max part size param must be greater than zero")
+	}
+	return func(o *PartsOptions) {
+		o.MaxPartSize = n
+	}
+}
+
+func MemoryThreshold(n int64) PartsOption {
+	if n < 0 {
+		panic("memory threshold param cannot be negative")
+	}
+	return func(o *PartsOptions) {
+		o.MemoryThreshold = n
+	}
+}
+
+func MaxPartsSize(n int64) PartsOption {
+	if n <= 0 {
+		panic("max parts size param must be greater than zero")
+	}
+	return func(o *PartsOptions) {
+		o.MaxPartsSize = n
+	}
+}
+
+func MaxParts(n int) PartsOption {
+	if n <= 0 {
+		panic("max parts param must be greater than zero")
+	}
+	return func(o *PartsOptions) {
+		o.MaxParts = n
+	}
+}
+
+func TempDir(dir string) PartsOption {
+	return func(o *PartsOptions) {
+		o.TempDir = dir
+	}
+}
+
+// TooManyPartsError is returned when a multipart
+// body contains more parts than the limit.
+type TooManyPartsError struct {
+	Limit int
+}
+
+func (e *TooManyPartsError) Error() string {
+	return fmt.Sprintf("must not contain more than %d parts", e.Limit)
+}
+
+// Part is a part of a multipart body which has been fully read. Its
+// content is kept in memory or in a temporary file, which is removed
+// when the request context ends.
+type Part struct {
+	Name        string
+	Filename    string
+	ContentType string
+	Size        int64
+	// Hex encoded SHA-256 checksum of the content.
+	Checksum string
+	// Path of the temporary file, empty if the content is in memory.
+	Path string
+
+	data []byte
+}
+
+// Open returns a reader of the part content.
+func (p *Part) Open() (io.ReadCloser, error) {
+	if p.Path != "" {
+		return os.Open(p.Path)
+	}
+	return io.NopCloser(bytes.NewReader(p.data)), nil
+}
+
+// PartReader iterates the parts of a multipart body without
+// buffering the whole body in memory.
+type PartReader struct {
+	reader  *multipart.Reader
+	options PartsOptions
+
+	parts int
+
+	mu     sync.Mutex
+	files  []string
+	closed bool
+}
+
+// Parts returns a PartReader for the multipart/form-data request body.
+// The temporary files are removed when the request context is done.
+// Parts are read lazily, so w is needed to close the connection as soon
+// as Next goes beyond MaxPartsSize rather than when the handler returns.
+func Parts(w http.ResponseWriter, r *http.Request, opts ...PartsOption) (*PartReader, error) {
+	o := DefaultPartsOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" || params["boundary"] == "" {
+		return nil, &UnsupportedMediaTypeError{MediaType: mediaType}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, o.MaxPartsSize)
+	pr := &PartReader{
+		reader:  multipart.NewReader(r.Body, params["boundary"]),
+		options: o,
+	}
+	context.AfterFunc(r.Context(), pr.cleanup)
+	return pr, nil
+}
+
+// Next reads the next part. It returns io.EOF when there are no more parts.
+func (pr *PartReader) Next() (*Part, error) {
+	mp, err := pr.reader.NextPart()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		if tooLarge := asTooLarge(err); tooLarge != nil {
+			return nil, tooLarge
+		}
+		return nil, &SyntaxError{Format: "multipart data"}
+	}
+	defer mp.Close()
+
+	if pr.parts >= pr.options.MaxParts {
+		return nil, &TooManyPartsError{Limit: pr.options.MaxParts}
+	}
+	pr.parts++
+
+	part := &Part{
+		Name:        mp.FormName(),
+		Filename:    mp.FileName(),
+		ContentType: mp.Header.Get("Content-Type"),
+	}
+
+	hash := sha256.New()
+	// Read one byte more than the limit to know if it has been exceeded.
+	src := io.TeeReader(io.LimitReader(mp, pr.options.MaxPartSize+1), hash)
+
+	// Keep the content in memory until it goes over the threshold.
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, src, pr.options.MemoryThreshold+1)
+	if err != nil && !errors.Is(err, io.EOF) {
+		if tooLarge := asTooLarge(err); tooLarge != nil {
+			return nil, tooLarge
+		}
+		return nil, err
+	}
+
+	if n > pr.options.MemoryThreshold {
+		m, err := pr.spool(part, &buf, src)
+		if err != nil {
+			if tooLarge := asTooLarge(err); tooLarge != nil {
+				return nil, tooLarge
+			}
+			return nil, err
+		}
+		n += m
+	} else {
+		part.data = buf.Bytes()
+	}
+
+	if n > pr.options.MaxPartSize {
+		pr.remove(part.Path)
+		return nil, &FileTooLargeError{Field: part.Name, Limit: pr.options.MaxPartSize}
+	}
+
+	part.Size = n
+	part.Checksum = hex.EncodeToString(hash.Sum(nil))
+	return part, nil
+}
+
+// asTooLarge returns a TooLargeError if err comes from
+// reading beyond MaxPartsSize, otherwise nil.
+func asTooLarge(err error) error {
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		return &TooLargeError{Limit: maxBytesError.Limit}
+	}
+	return nil
+}
+
+// spool writes the buffered content and the rest of src to a temporary
+// file, returning the number of bytes read from src.
+func (pr *PartReader) spool(part *Part, buf *bytes.Buffer, src io.Reader) (int64, error) {
+	f, err := os.CreateTemp(pr.options.TempDir, "modular-part-*")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	part.Path = f.Name()
+	if !pr.track(part.Path) {
+		os.Remove(part.Path)
+		return 0, errors.New("request has already finished")
+	}
+
+	if _, err := buf.WriteTo(f); err != nil {
+		pr.remove(part.Path)
+		return 0, err
+	}
+	n, err := io.Copy(f, src)
+	if err != nil {
+		pr.remove(part.Path)
+		return 0, err
+	}
+	return n, nil
+}
+
+// track records a temporary file to be removed on cleanup.
+// It reports false if the cleanup has already happened.
+func (pr *PartReader) track(path string) bool {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if pr.closed {
+		return false
+	}
+	pr.files = append(pr.files, path)
+	return true
+}
+
+func (pr *PartReader) remove(path string) {
+	if path != "" {
+		os.Remove(path)
+	}
+}
+
+// cleanup removes every temporary file created by the reader.
+func (pr *PartReader) cleanup() {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.closed = true
+	for _, path := range pr.files {
+		os.Remove(path)
+	}
+	pr.files = nil
+}
@@ -0,0 +1,178 @@
+package request
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParts(t *testing.T) {
+	small := []byte("small")
+	large := []byte(strings.Repeat("large", 10))
+
+	t.Run("memory and spooled parts", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		r := multipartRequest(t, nil, map[string][][]byte{"file": {small, large}}).WithContext(ctx)
+		dir := t.TempDir()
+
+		pr, err := Parts(httptest.NewRecorder(), r, MemoryThreshold(16), TempDir(dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var parts []*Part
+		for {
+			p, err := pr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			parts = append(parts, p)
+		}
+
+		if len(parts) != 2 {
+			t.Fatalf("got %d parts, but want %d", len(parts), 2)
+		}
+		if parts[0].Path != "" {
+			t.Errorf("small part was spooled to a file")
+		}
+		if parts[1].Path == "" {
+			t.Errorf("large part was not spooled to a file")
+		}
+
+		for i, want := range [][]byte{small, large} {
+			assertPart(t, parts[i], want)
+		}
+
+		// Temporary files are removed when the request ends,
+		// the cleanup runs in its own goroutine.
+		cancel()
+		removed := false
+		for i := 0; i < 100 && !removed; i++ {
+			_, err := os.Stat(parts[1].Path)
+			removed = errors.Is(err, os.ErrNotExist)
+			time.Sleep(10 * time.Millisecond)
+		}
+		if !removed {
+			t.Errorf("temporary file %q was not removed", parts[1].Path)
+		}
+	})
+
+	t.Run("part too large", func(t *testing.T) {
+		r := multipartRequest(t, nil, map[string][][]byte{"file": {large}})
+
+		pr, err := Parts(httptest.NewRecorder(), r, MaxPartSize(8), MemoryThreshold(4), TempDir(t.TempDir()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = pr.Next()
+		var tooLarge *FileTooLargeError
+		if !errors.As(err, &tooLarge) {
+			t.Errorf("got error %v, but want file too large", err)
+		}
+	})
+
+	t.Run("too many parts", func(t *testing.T) {
+		r := multipartRequest(t, nil, map[string][][]byte{"file": {small, small, small}})
+
+		pr, err := Parts(httptest.NewRecorder(), r, MaxParts(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 2; i++ {
+			if _, err := pr.Next(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, err = pr.Next()
+		var tooMany *TooManyPartsError
+		if !errors.As(err, &tooMany) || tooMany.Limit != 2 {
+			t.Errorf("got error %v, but want too many parts", err)
+		}
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		r := multipartRequest(t, nil, map[string][][]byte{"file": {large, large, large}})
+		dir := t.TempDir()
+
+		pr, err := Parts(httptest.NewRecorder(), r, MaxPartsSize(256), MemoryThreshold(4), TempDir(dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for {
+			_, err = pr.Next()
+			if err != nil {
+				break
+			}
+		}
+		var tooLarge *TooLargeError
+		if !errors.As(err, &tooLarge) || tooLarge.Limit != 256 {
+			t.Errorf("got error %v, but want body too large", err)
+		}
+	})
+
+	t.Run("badly-formed body", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("--xyz\r\nno header separator\r\n\r\ncontent"))
+		r.Header.Set("Content-Type", "multipart/form-data; boundary=xyz")
+
+		pr, err := Parts(httptest.NewRecorder(), r)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = pr.Next()
+		var syntax *SyntaxError
+		if !errors.As(err, &syntax) || syntax.Format != "multipart data" {
+			t.Errorf("got error %v, but want badly-formed multipart data", err)
+		}
+	})
+
+	t.Run("unsupported media type", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))
+
+		_, err := Parts(httptest.NewRecorder(), r)
+		var mediaType *UnsupportedMediaTypeError
+		if !errors.As(err, &mediaType) {
+			t.Errorf("got error %v, but want unsupported media type", err)
+		}
+	})
+}
+
+func assertPart(t testing.TB, p *Part, want []byte) {
+	t.Helper()
+
+	f, err := p.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("got content %q, but want %q", got, want)
+	}
+	if p.Size != int64(len(want)) {
+		t.Errorf("got size %d, but want %d", p.Size, len(want))
+	}
+
+	sum := sha256.Sum256(want)
+	if p.Checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("got checksum %s, but want %x", p.Checksum, sum)
+	}
+}
@@ -0,0 +1,199 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"net/mail"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError describes why a field is not valid.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldErrors is the list of fields which are not valid.
+type FieldErrors []FieldError
+
+func (fe FieldErrors) Error() string {
+	messages := make([]string, len(fe))
+	for i, e := range fe {
+		messages[i] = fmt.Sprintf("%s %s", e.Field, e.Message)
+	}
+	return strings.Join(messages, ", ")
+}
+
+// ReadValid reads the request body into dst like Read and then
+// validates it. Validation errors are returned as FieldErrors.
+func ReadValid(w http.ResponseWriter, r *http.Request, dst any, opts ...Option) error {
+	if err := Read(w, r, dst, opts...); err != nil {
+		return err
+	}
+	return Validate(dst)
+}
+
+// Validate checks the struct pointed by dst using the rules of the
+// validate tag of its fields, e.g. `validate:"required,min=3,max=64"`.
+//
+//   - required: the field must not be the zero value.
+//   - min=n, max=n, len=n: length of strings, slices and maps, value of numbers.
+//   - email: the string must be a valid email address.
+//   - oneof=a b c: the value must be one of the space separated options.
+//
+// Fields which are not required are only validated when they are set.
+// It returns nil or FieldErrors with every invalid field.
+func Validate(dst any) error {
+	v := reflect.ValueOf(dst)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			panic("dst param cannot be nil")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		panic("dst param must be a struct")
+	}
+
+	var errs FieldErrors
+	validateStruct(v, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// fieldName returns the name used by the JSON encoding of the field.
+func fieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
+
+func validateStruct(v reflect.Value, prefix string, errs *FieldErrors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name := prefix + fieldName(f)
+		value := v.Field(i)
+		for value.Kind() == reflect.Pointer && !value.IsNil() {
+			value = value.Elem()
+		}
+
+		if tag := f.Tag.Get("validate"); tag != "" {
+			if msg := validateField(value, tag); msg != "" {
+				*errs = append(*errs, FieldError{name, msg})
+				continue
+			}
+		}
+
+		if value.Kind() == reflect.Struct {
+			validateStruct(value, name+".", errs)
+		}
+	}
+}
+
+// validateField returns the message of the first rule
+// the value does not satisfy, or an empty string.
+func validateField(v reflect.Value, tag string) string {
+	rules := strings.Split(tag, ",")
+
+	if !v.IsValid() || v.IsZero() {
+		if slices.Contains(rules, "required") {
+			return "is required"
+		}
+		return ""
+	}
+
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(rule, "=")
+		if msg := check(v, name, arg); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+func check(v reflect.Value, rule, arg string) string {
+	switch rule {
+	case "required":
+		return ""
+	case "min":
+		if size(v, rule) < number(rule, arg) {
+			return fmt.Sprintf("must be at least %s", limit(v, arg))
+		}
+	case "max":
+		if size(v, rule) > number(rule, arg) {
+			return fmt.Sprintf("must be at most %s", limit(v, arg))
+		}
+	case "len":
+		if size(v, rule) != number(rule, arg) {
+			return fmt.Sprintf("must be exactly %s", limit(v, arg))
+		}
+	case "email":
+		s := text(v, rule)
+		if a, err := mail.ParseAddress(s); err != nil || a.Address != s {
+			return "must be a valid email address"
+		}
+	case "oneof":
+		options := strings.Fields(arg)
+		if !slices.Contains(options, fmt.Sprint(v.Interface())) {
+			return fmt.Sprintf("must be one of: %s", strings.Join(options, ", "))
+		}
+	default:
+		panic(fmt.Sprintf("validation rule %q is unknown", rule))
+	}
+	return ""
+}
+
+// size returns the length or the value of v compared by the rule.
+func size(v reflect.Value, rule string) float64 {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String()))
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	panic(fmt.Sprintf("validation rule %q cannot be applied to %s", rule, v.Kind()))
+}
+
+func limit(v reflect.Value, arg string) string {
+	switch v.Kind() {
+	case reflect.String:
+		return arg + " characters long"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return arg + " items long"
+	}
+	return arg
+}
+
+func number(rule, arg string) float64 {
+	n, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validation rule %q needs a number", rule))
+	}
+	return n
+}
+
+func text(v reflect.Value, rule string) string {
+	if v.Kind() != reflect.String {
+		panic(fmt.Sprintf("validation rule %q cannot be applied to %s", rule, v.Kind()))
+	}
+	return v.String()
+}
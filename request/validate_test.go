@@ -0,0 +1,126 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nukiro/modular/internal/tests"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type user struct {
+	Name    string   `json:"name" validate:"required,min=3,max=8"`
+	Email   string   `json:"email" validate:"required,email"`
+	Age     int      `json:"age" validate:"min=18,max=99"`
+	Role    string   `json:"role" validate:"oneof=admin member"`
+	Tags    []string `json:"tags" validate:"max=2"`
+	Code    string   `validate:"len=4"`
+	Address *address `json:"address"`
+}
+
+func TestValidate(t *testing.T) {
+	valid := user{
+		Name:    "gopher",
+		Email:   "gopher@example.com",
+		Age:     30,
+		Role:    "admin",
+		Tags:    []string{"go"},
+		Code:    "ABCD",
+		Address: &address{City: "Madrid"},
+	}
+
+	errs := []struct {
+		name   string
+		change func(*user)
+		want   FieldErrors
+	}{
+		{"valid", func(u *user) {}, nil},
+		{"optional fields", func(u *user) { u.Age, u.Role, u.Tags, u.Code, u.Address = 0, "", nil, "", nil }, nil},
+		{"required", func(u *user) { u.Name = "" }, FieldErrors{{"name", "is required"}}},
+		{"min length", func(u *user) { u.Name = "go" }, FieldErrors{{"name", "must be at least 3 characters long"}}},
+		{"max length", func(u *user) { u.Name = "gophers!!" }, FieldErrors{{"name", "must be at most 8 characters long"}}},
+		{"email", func(u *user) { u.Email = "gopher" }, FieldErrors{{"email", "must be a valid email address"}}},
+		{"min value", func(u *user) { u.Age = 17 }, FieldErrors{{"age", "must be at least 18"}}},
+		{"one of", func(u *user) { u.Role = "owner" }, FieldErrors{{"role", "must be one of: admin, member"}}},
+		{"max items", func(u *user) { u.Tags = []string{"a", "b", "c"} }, FieldErrors{{"tags", "must be at most 2 items long"}}},
+		{"exact length", func(u *user) { u.Code = "ABC" }, FieldErrors{{"Code", "must be exactly 4 characters long"}}},
+		{"nested struct", func(u *user) { u.Address.City = "" }, FieldErrors{{"address.city", "is required"}}},
+		{"several fields", func(u *user) { u.Name, u.Email = "", "" }, FieldErrors{{"name", "is required"}, {"email", "is required"}}},
+	}
+
+	for _, tt := range errs {
+		t.Run(tt.name, func(t *testing.T) {
+			u := valid
+			a := *valid.Address
+			u.Address = &a
+			tt.change(&u)
+
+			err := Validate(&u)
+
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("got error %q, but want none", err)
+				}
+				return
+			}
+
+			var got FieldErrors
+			if !errors.As(err, &got) {
+				t.Fatalf("got error %v, but want field errors", err)
+			}
+			if got.Error() != tt.want.Error() {
+				t.Errorf("got %q, but want %q", got.Error(), tt.want.Error())
+			}
+		})
+	}
+
+	t.Run("nil destination", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "Validate", "dst")
+		}()
+
+		var u *user
+		Validate(u)
+	})
+
+	t.Run("unknown rule", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanic(t, recover(), "Validate", `validation rule "unknown" is unknown`)
+		}()
+
+		Validate(&struct {
+			Name string `validate:"unknown"`
+		}{"gopher"})
+	})
+}
+
+func TestReadValid(t *testing.T) {
+	t.Run("valid body", func(t *testing.T) {
+		body := `{"name": "gopher", "email": "gopher@example.com"}`
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+		var u user
+		if err := ReadValid(httptest.NewRecorder(), r, &u); err != nil {
+			t.Errorf("got error %q, but want none", err)
+		}
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		body := `{"name": "go", "email": "gopher@example.com"}`
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+		var u user
+		err := ReadValid(httptest.NewRecorder(), r, &u)
+
+		var got FieldErrors
+		if !errors.As(err, &got) || len(got) != 1 {
+			t.Errorf("got error %v, but want one field error", err)
+		}
+	})
+}
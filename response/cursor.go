@@ -0,0 +1,28 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// CursorBody is the envelope of a list paginated with cursors.
+type CursorBody struct {
+	Data       any    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// CursorPaginated sends the data with the cursor of the next page, empty
+// for the last one. The link to the next page is sent in the Link header.
+func CursorPaginated(w http.ResponseWriter, r *http.Request, data any, next string) *Response {
+	rw := New(http.StatusOK)
+
+	if next != "" {
+		u := *r.URL
+		q := u.Query()
+		q.Set("cursor", next)
+		u.RawQuery = q.Encode()
+		rw.Header.Set("Link", fmt.Sprintf(`<%s>; rel="next"`, u.RequestURI()))
+	}
+
+	return rw.JSON(w, CursorBody{Data: data, NextCursor: next})
+}
@@ -0,0 +1,44 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCursorPaginated(t *testing.T) {
+	pages := []struct {
+		name   string
+		next   string
+		header string
+	}{
+		{"next page", "abc", `</articles?cursor=abc&limit=10>; rel="next"`},
+		{"last page", "", ""},
+	}
+
+	for _, tt := range pages {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/articles?limit=10&cursor=old", nil)
+
+			CursorPaginated(w, r, []string{"a", "b"}, tt.next)
+
+			var body map[string]any
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			next, ok := body["next_cursor"]
+			if tt.next == "" && ok {
+				t.Errorf("got next cursor %v, but want none", next)
+			}
+			if tt.next != "" && next != tt.next {
+				t.Errorf("got next cursor %v, but want %q", next, tt.next)
+			}
+			if got := w.Header().Get("Link"); got != tt.header {
+				t.Errorf("got link header %q, but want %q", got, tt.header)
+			}
+		})
+	}
+}
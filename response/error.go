@@ -0,0 +1,68 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+)
+
+// AppError is an error returned by the application carrying how it
+// must be sent to the client. Message is public, while the wrapped
+// error is the internal cause and it is only logged.
+type AppError struct {
+	Status  int
+	Code    string
+	Message string
+	Err     error
+}
+
+func NewError(status int, code, message string, err error) *AppError {
+	checkCode(status)
+	return &AppError{
+		Status:  status,
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// internalError is used for errors which are not an AppError,
+// they must never reach the client.
+var internalError = &AppError{
+	Status:  http.StatusInternalServerError,
+	Code:    "internal_error",
+	Message: "the server encountered a problem and could not process your request",
+}
+
+// Fail sends err to the client as a problem. An AppError is sent with
+// its status, code and message, any other error as an internal error.
+// The internal cause is only included in the body when expose is true.
+func Fail(w http.ResponseWriter, r *http.Request, err error, expose bool) *Response {
+	if err == nil {
+		panic("err param cannot be nil")
+	}
+
+	appErr := internalError
+	errors.As(err, &appErr)
+
+	p := NewProblem(appErr.Status, appErr.Message)
+	p.Instance = r.URL.Path
+	if appErr.Code != "" {
+		p.Extensions["code"] = appErr.Code
+	}
+	if expose {
+		p.Extensions["cause"] = err.Error()
+	}
+
+	return p.Write(w)
+}
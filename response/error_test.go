@@ -0,0 +1,85 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nukiro/modular/internal/tests"
+)
+
+func TestAppError(t *testing.T) {
+	cause := errors.New("duplicate key")
+	err := NewError(http.StatusConflict, "article_exists", "the article already exists", cause)
+
+	if err.Error() != "the article already exists: duplicate key" {
+		t.Errorf("got error %q, but want %q", err.Error(), "the article already exists: duplicate key")
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("error does not wrap the cause")
+	}
+
+	err = NewError(http.StatusNotFound, "article_not_found", "the article does not exist", nil)
+	if err.Error() != "the article does not exist" {
+		t.Errorf("got error %q, but want %q", err.Error(), "the article does not exist")
+	}
+}
+
+func TestFail(t *testing.T) {
+	cause := errors.New("duplicate key")
+
+	t.Run("application error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/articles", nil)
+		err := NewError(http.StatusConflict, "article_exists", "the article already exists", cause)
+
+		Fail(w, r, err, false)
+
+		body := assertProblem(t, w, http.StatusConflict)
+		assertBody(t, body, "detail", "the article already exists")
+		assertBody(t, body, "code", "article_exists")
+		assertBody(t, body, "instance", "/articles")
+		if _, ok := body["cause"]; ok {
+			t.Errorf("internal cause was exposed")
+		}
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/articles", nil)
+
+		Fail(w, r, cause, false)
+
+		body := assertProblem(t, w, http.StatusInternalServerError)
+		assertBody(t, body, "code", "internal_error")
+		if _, ok := body["cause"]; ok {
+			t.Errorf("internal cause was exposed")
+		}
+	})
+
+	t.Run("exposed internal cause", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/articles", nil)
+
+		Fail(w, r, cause, true)
+
+		body := assertProblem(t, w, http.StatusInternalServerError)
+		assertBody(t, body, "cause", "duplicate key")
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "Fail", "err")
+		}()
+
+		Fail(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), nil, false)
+	})
+}
+
+func assertBody(t testing.TB, body map[string]any, key string, value any) {
+	t.Helper()
+	if body[key] != value {
+		t.Errorf("got %s %v, but want %v", key, body[key], value)
+	}
+}
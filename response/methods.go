@@ -42,12 +42,12 @@ var write = func(w http.ResponseWriter, f Serializer, r *Response) error {
 
 	// At this point it is safe to add any headers as we know that we will not
 	// encounter any more errors before writing the response.
+	// Response Content Type, it can be overridden by the custom headers.
+	w.Header().Set("Content-Type", "application/json")
 	// Custom headers value pass by param method.
 	for key, value := range r.Header {
 		w.Header()[key] = value
 	}
-	// Response Content Type
-	w.Header().Set("Content-Type", "application/json")
 	// Response Status Code
 	w.WriteHeader(r.Code)
 
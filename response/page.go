@@ -0,0 +1,75 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nukiro/modular/request"
+)
+
+// PageMeta describes the page sent to the client.
+type PageMeta struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// PageLinks are the URLs of the current, next and previous pages.
+type PageLinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// PageBody is the envelope of a paginated list.
+type PageBody struct {
+	Data  any       `json:"data"`
+	Meta  PageMeta  `json:"meta"`
+	Links PageLinks `json:"links"`
+}
+
+// pageURL returns the current request URL with the given offset,
+// keeping every other query parameter.
+func pageURL(u *url.URL, page request.Page, offset int) string {
+	q := u.Query()
+	q.Set("limit", strconv.Itoa(page.Limit))
+	q.Set("offset", strconv.Itoa(offset))
+
+	next := *u
+	next.RawQuery = q.Encode()
+	return next.RequestURI()
+}
+
+// Paginated sends the data of the page with its total count and the links
+// to the next and previous pages, which are also sent in the Link header.
+func Paginated(w http.ResponseWriter, r *http.Request, data any, total int, page request.Page) *Response {
+	links := PageLinks{Self: pageURL(r.URL, page, page.Offset)}
+	// Written as a subtraction, so large offsets do not overflow.
+	if page.Offset < total-page.Limit {
+		links.Next = pageURL(r.URL, page, page.Offset+page.Limit)
+	}
+	if page.Offset > 0 {
+		links.Prev = pageURL(r.URL, page, max(page.Offset-page.Limit, 0))
+	}
+
+	rw := New(http.StatusOK)
+	var header []string
+	if links.Next != "" {
+		header = append(header, fmt.Sprintf(`<%s>; rel="next"`, links.Next))
+	}
+	if links.Prev != "" {
+		header = append(header, fmt.Sprintf(`<%s>; rel="prev"`, links.Prev))
+	}
+	if len(header) > 0 {
+		rw.Header.Set("Link", strings.Join(header, ", "))
+	}
+
+	return rw.JSON(w, PageBody{
+		Data:  data,
+		Meta:  PageMeta{Total: total, Limit: page.Limit, Offset: page.Offset},
+		Links: links,
+	})
+}
@@ -0,0 +1,55 @@
+package response
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nukiro/modular/request"
+)
+
+func TestPaginated(t *testing.T) {
+	pages := []struct {
+		name   string
+		page   request.Page
+		next   string
+		prev   string
+		header string
+	}{
+		{"first page", request.Page{Limit: 10}, "/articles?limit=10&offset=10&sort=name", "", `</articles?limit=10&offset=10&sort=name>; rel="next"`},
+		{"middle page", request.Page{Limit: 10, Offset: 5}, "/articles?limit=10&offset=15&sort=name", "/articles?limit=10&offset=0&sort=name",
+			`</articles?limit=10&offset=15&sort=name>; rel="next", </articles?limit=10&offset=0&sort=name>; rel="prev"`},
+		{"last page", request.Page{Limit: 10, Offset: 20}, "", "/articles?limit=10&offset=10&sort=name", `</articles?limit=10&offset=10&sort=name>; rel="prev"`},
+		{"offset out of range", request.Page{Limit: 10, Offset: math.MaxInt}, "", "/articles?limit=10&offset=9223372036854775797&sort=name",
+			`</articles?limit=10&offset=9223372036854775797&sort=name>; rel="prev"`},
+	}
+
+	for _, tt := range pages {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/articles?sort=name", nil)
+
+			Paginated(w, r, []string{"a", "b"}, 25, tt.page)
+
+			var body PageBody
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if body.Meta.Total != 25 || body.Meta.Limit != tt.page.Limit || body.Meta.Offset != tt.page.Offset {
+				t.Errorf("got meta %+v", body.Meta)
+			}
+			if body.Links.Next != tt.next {
+				t.Errorf("got next %q, but want %q", body.Links.Next, tt.next)
+			}
+			if body.Links.Prev != tt.prev {
+				t.Errorf("got prev %q, but want %q", body.Links.Prev, tt.prev)
+			}
+			if got := w.Header().Get("Link"); got != tt.header {
+				t.Errorf("got link header %q, but want %q", got, tt.header)
+			}
+		})
+	}
+}
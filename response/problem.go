@@ -0,0 +1,159 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/nukiro/modular/request"
+)
+
+// Problem is an error response body as defined by RFC 9457,
+// sent with the application/problem+json content type.
+type Problem struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+	// Extensions members are added to the top level object,
+	// such as the list of field validation errors.
+	Extensions map[string]any
+}
+
+func NewProblem(status int, detail string) *Problem {
+	checkCode(status)
+	return &Problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     detail,
+		Extensions: make(map[string]any),
+	}
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		m[key] = value
+	}
+
+	m["type"] = p.Type
+	m["title"] = p.Title
+	m["status"] = p.Status
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+
+	return json.Marshal(m)
+}
+
+// Write sends the problem to the client.
+func (p *Problem) Write(w http.ResponseWriter) *Response {
+	rw := New(p.Status)
+	rw.Header.Set("Content-Type", "application/problem+json")
+	return rw.JSON(w, p)
+}
+
+// Error sends a problem with the given status code. Server errors
+// do not expose err to the client, it should be logged instead.
+// Validation errors are added to the errors member.
+func Error(w http.ResponseWriter, code int, err error) *Response {
+	p := NewProblem(code, "")
+
+	var fields request.FieldErrors
+	switch {
+	case code >= http.StatusInternalServerError:
+		p.Detail = "the server encountered a problem and could not process your request"
+	case errors.As(err, &fields):
+		p.Detail = "the request contains invalid fields"
+		p.Extensions["errors"] = fields
+	case err != nil:
+		p.Detail = err.Error()
+	}
+
+	return p.Write(w)
+}
+
+func BadRequest(w http.ResponseWriter, err error) *Response {
+	return Error(w, http.StatusBadRequest, err)
+}
+
+func NotFound(w http.ResponseWriter, err error) *Response {
+	return Error(w, http.StatusNotFound, err)
+}
+
+func Conflict(w http.ResponseWriter, err error) *Response {
+	return Error(w, http.StatusConflict, err)
+}
+
+func Unprocessable(w http.ResponseWriter, err error) *Response {
+	return Error(w, http.StatusUnprocessableEntity, err)
+}
+
+func InternalError(w http.ResponseWriter, err error) *Response {
+	return Error(w, http.StatusInternalServerError, err)
+}
+
+// InvalidBody sends a problem for an error returned by request.Read,
+// ReadValid, Decode, Form or a PartReader. Bodies and files larger
+// than the limit, or with too many parts, are sent as 413, unsupported
+// media types as 415, validation errors as 422 and any other error
+// as 400.
+func InvalidBody(w http.ResponseWriter, err error) *Response {
+	if err == nil {
+		panic("err param cannot be nil")
+	}
+
+	var fields request.FieldErrors
+	if errors.As(err, &fields) {
+		return Unprocessable(w, err)
+	}
+
+	var syntax *request.SyntaxError
+	var tooLarge *request.TooLargeError
+	var fileTooLarge *request.FileTooLargeError
+	var tooManyParts *request.TooManyPartsError
+	var mediaType *request.UnsupportedMediaTypeError
+	var fileType *request.FileTypeError
+	var typeError *request.TypeError
+	var unknownField *request.UnknownFieldError
+	var multiple *request.MultipleValuesError
+	var p *Problem
+	switch {
+	case errors.As(err, &syntax):
+		p = NewProblem(http.StatusBadRequest, "body "+syntax.Error())
+	case errors.As(err, &tooLarge):
+		p = NewProblem(http.StatusRequestEntityTooLarge, "body "+tooLarge.Error())
+		p.Extensions["limit"] = tooLarge.Limit
+	case errors.As(err, &fileTooLarge):
+		p = NewProblem(http.StatusRequestEntityTooLarge, fileTooLarge.Error())
+		p.Extensions["field"] = fileTooLarge.Field
+		p.Extensions["limit"] = fileTooLarge.Limit
+	case errors.As(err, &tooManyParts):
+		p = NewProblem(http.StatusRequestEntityTooLarge, "body "+tooManyParts.Error())
+		p.Extensions["limit"] = tooManyParts.Limit
+	case errors.As(err, &mediaType):
+		p = NewProblem(http.StatusUnsupportedMediaType, mediaType.Error())
+	case errors.As(err, &fileType):
+		p = NewProblem(http.StatusUnsupportedMediaType, fileType.Error())
+		p.Extensions["field"] = fileType.Field
+	case errors.As(err, &typeError):
+		p = NewProblem(http.StatusBadRequest, "body "+typeError.Error())
+		if typeError.Field != "" {
+			p.Extensions["field"] = typeError.Field
+		}
+	case errors.As(err, &unknownField):
+		p = NewProblem(http.StatusBadRequest, "body "+unknownField.Error())
+		p.Extensions["field"] = unknownField.Field
+	case errors.As(err, &multiple):
+		p = NewProblem(http.StatusBadRequest, "body "+multiple.Error())
+	default:
+		p = NewProblem(http.StatusBadRequest, err.Error())
+	}
+
+	return p.Write(w)
+}
@@ -0,0 +1,162 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nukiro/modular/internal/tests"
+	"github.com/nukiro/modular/request"
+)
+
+func TestNewProblem(t *testing.T) {
+	p := NewProblem(http.StatusNotFound, "article not found")
+
+	if p.Type != "about:blank" {
+		t.Errorf("got type %q, but want %q", p.Type, "about:blank")
+	}
+	if p.Title != "Not Found" {
+		t.Errorf("got title %q, but want %q", p.Title, "Not Found")
+	}
+	if p.Status != http.StatusNotFound {
+		t.Errorf("got status %d, but want %d", p.Status, http.StatusNotFound)
+	}
+	if p.Extensions == nil {
+		t.Errorf("extensions were not initialized")
+	}
+}
+
+func TestProblemMarshalJSON(t *testing.T) {
+	p := NewProblem(http.StatusConflict, "")
+	p.Instance = "/articles/1"
+	p.Extensions["version"] = 2
+
+	js, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"instance":"/articles/1","status":409,"title":"Conflict","type":"about:blank","version":2}`
+	if string(js) != want {
+		t.Errorf("got %s, but want %s", js, want)
+	}
+}
+
+func TestError(t *testing.T) {
+	errs := []struct {
+		name   string
+		send   func(http.ResponseWriter, error) *Response
+		code   int
+		detail string
+	}{
+		{"bad request", BadRequest, http.StatusBadRequest, "an error"},
+		{"not found", NotFound, http.StatusNotFound, "an error"},
+		{"conflict", Conflict, http.StatusConflict, "an error"},
+		{"unprocessable", Unprocessable, http.StatusUnprocessableEntity, "an error"},
+		{"internal error", InternalError, http.StatusInternalServerError, "the server encountered a problem and could not process your request"},
+		{"invalid body", InvalidBody, http.StatusBadRequest, "an error"},
+	}
+
+	for _, tt := range errs {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.send(w, errors.New("an error"))
+
+			body := assertProblem(t, w, tt.code)
+			if body["detail"] != tt.detail {
+				t.Errorf("got detail %q, but want %q", body["detail"], tt.detail)
+			}
+		})
+	}
+
+	t.Run("without error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		Error(w, http.StatusNotFound, nil)
+
+		body := assertProblem(t, w, http.StatusNotFound)
+		if _, ok := body["detail"]; ok {
+			t.Errorf("got detail %q, but want no detail", body["detail"])
+		}
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		Unprocessable(w, request.FieldErrors{{Field: "name", Message: "is required"}})
+
+		body := assertProblem(t, w, http.StatusUnprocessableEntity)
+		errs, ok := body["errors"].([]any)
+		if !ok || len(errs) != 1 {
+			t.Fatalf("got errors %v, but want one field error", body["errors"])
+		}
+		if field := errs[0].(map[string]any); field["field"] != "name" || field["message"] != "is required" {
+			t.Errorf("got field error %v, but want name is required", field)
+		}
+	})
+
+	t.Run("invalid body errors", func(t *testing.T) {
+		bodies := []struct {
+			err   error
+			code  int
+			key   string
+			value any
+		}{
+			{&request.SyntaxError{Offset: 4}, http.StatusBadRequest, "detail", "body contains badly-formed JSON (at character 4)"},
+			{&request.TypeError{Field: "title"}, http.StatusBadRequest, "field", "title"},
+			{&request.UnknownFieldError{Field: "author"}, http.StatusBadRequest, "field", "author"},
+			{&request.EmptyBodyError{}, http.StatusBadRequest, "detail", "body must not be empty"},
+			{&request.MultipleValuesError{}, http.StatusBadRequest, "detail", "body must only contain a single JSON value"},
+			{&request.TooLargeError{Limit: 8}, http.StatusRequestEntityTooLarge, "limit", float64(8)},
+			{&request.TooLargeError{Limit: 8}, http.StatusRequestEntityTooLarge, "detail", "body must not be larger than 8 bytes"},
+			{&request.UnknownFieldError{Field: "author"}, http.StatusBadRequest, "detail", "body contains unknown key: author"},
+			{&request.FileTooLargeError{Field: "avatar", Limit: 8}, http.StatusRequestEntityTooLarge, "field", "avatar"},
+			{&request.FileTooLargeError{Field: "avatar", Limit: 8}, http.StatusRequestEntityTooLarge, "detail", "file avatar must not be larger than 8 bytes"},
+			{&request.TooManyPartsError{Limit: 2}, http.StatusRequestEntityTooLarge, "detail", "body must not contain more than 2 parts"},
+			{&request.FileTypeError{Field: "avatar", MediaType: "text/plain"}, http.StatusUnsupportedMediaType, "field", "avatar"},
+			{&request.FileTypeError{Field: "avatar", MediaType: "text/plain"}, http.StatusUnsupportedMediaType, "detail", `file avatar media type "text/plain" is not allowed`},
+			{&request.UnsupportedMediaTypeError{MediaType: "text/plain"}, http.StatusUnsupportedMediaType, "detail", `media type "text/plain" is not supported`},
+			{request.FieldErrors{{Field: "title", Message: "is required"}}, http.StatusUnprocessableEntity, "detail", "the request contains invalid fields"},
+		}
+
+		for _, tt := range bodies {
+			t.Run(tt.err.Error(), func(t *testing.T) {
+				w := httptest.NewRecorder()
+				InvalidBody(w, tt.err)
+
+				body := assertProblem(t, w, tt.code)
+				if body[tt.key] != tt.value {
+					t.Errorf("got %s %v, but want %v", tt.key, body[tt.key], tt.value)
+				}
+			})
+		}
+	})
+
+	t.Run("invalid body with a nil error", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "InvalidBody", "err")
+		}()
+
+		InvalidBody(httptest.NewRecorder(), nil)
+	})
+}
+
+func assertProblem(t testing.TB, w *httptest.ResponseRecorder, code int) map[string]any {
+	t.Helper()
+
+	if w.Code != code {
+		t.Errorf("got status code %d, but want %d", w.Code, code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("got content type %q, but want %q", got, "application/problem+json")
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["status"] != float64(code) {
+		t.Errorf("got body status %v, but want %d", body["status"], code)
+	}
+	return body
+}
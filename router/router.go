@@ -1,75 +1,358 @@
 package router
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/nukiro/modular/request"
+	"github.com/nukiro/modular/response"
 )
 
 type Router interface {
 	Mux() http.Handler
-	Get(string, http.HandlerFunc)
-	Post(string, http.HandlerFunc)
-	Put(string, http.HandlerFunc)
-	Patch(string, http.HandlerFunc)
-	Delete(string, http.HandlerFunc)
+	Use(...Middleware)
+	Route(string) Router
+	Group(string, func(Router))
+	Get(string, http.HandlerFunc, ...Middleware)
+	Post(string, http.HandlerFunc, ...Middleware)
+	Put(string, http.HandlerFunc, ...Middleware)
+	Patch(string, http.HandlerFunc, ...Middleware)
+	Delete(string, http.HandlerFunc, ...Middleware)
+	Head(string, http.HandlerFunc, ...Middleware)
+	Options(string, http.HandlerFunc, ...Middleware)
+	Handle(string, string, http.HandlerFunc, ...Middleware)
+	Adapt(HandlerFunc) http.HandlerFunc
+	HandleErr(string, string, HandlerFunc, ...Middleware)
+}
+
+// HandlerFunc is a handler returning an error instead of writing it,
+// it is turned into an http.HandlerFunc by Router.Adapt.
+type HandlerFunc func(http.ResponseWriter, *http.Request) error
+
+// ErrorHandlerFunc sends the error returned by a HandlerFunc to the client.
+type ErrorHandlerFunc func(http.ResponseWriter, *http.Request, error)
+
+// Middleware wraps a handler to run code before and/or after it.
+type Middleware func(http.Handler) http.Handler
+
+// chain wraps the handler with the middlewares, the first one
+// being the outermost, so they run in the order they were added.
+func chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
 }
 
 type route struct {
-	method  string
-	path    string
-	handler http.HandlerFunc
+	method      string
+	path        string
+	handler     http.HandlerFunc
+	middlewares []Middleware
 }
 
-func buildRoute(method, path string, handler http.HandlerFunc) *route {
-	return &route{method, BuildPath(path), handler}
+func buildRoute(method, path string, handler http.HandlerFunc, middlewares []Middleware) *route {
+	return &route{method, BuildPath(path), handler, middlewares}
 }
 
 type router struct {
-	routes []*route
+	prefix      string
+	routes      []*route
+	middlewares []Middleware
+	groups      []*router
+
+	notFound         http.Handler
+	methodNotAllowed http.Handler
+	panicHandler     func(http.ResponseWriter, *http.Request, any)
+	exposeErrors     bool
+	errorHandler     ErrorHandlerFunc
 }
 
-func (r *router) Mux() http.Handler {
-	mux := httprouter.New()
+// Option configures how the router answers requests
+// that do not reach any of the registered routes.
+type Option func(*router)
+
+// NotFound sets the handler called when no route matches the request.
+func NotFound(h http.Handler) Option {
+	if h == nil {
+		panic("handler param cannot be nil")
+	}
+	return func(r *router) {
+		r.notFound = h
+	}
+}
+
+// MethodNotAllowed sets the handler called when the path matches a route,
+// but not the method. The Allow header is already set when it is called.
+func MethodNotAllowed(h http.Handler) Option {
+	if h == nil {
+		panic("handler param cannot be nil")
+	}
+	return func(r *router) {
+		r.methodNotAllowed = h
+	}
+}
+
+// PanicHandler sets the function called when a route handler panics.
+// Without it, the panic reaches the server recover middleware.
+func PanicHandler(h func(http.ResponseWriter, *http.Request, any)) Option {
+	if h == nil {
+		panic("handler param cannot be nil")
+	}
+	return func(r *router) {
+		r.panicHandler = h
+	}
+}
+
+// ExposeErrors includes the internal cause of the errors returned by
+// adapted handlers in the response body. It must be disabled in Production.
+func ExposeErrors(expose bool) Option {
+	return func(r *router) {
+		r.exposeErrors = expose
+	}
+}
+
+// ErrorHandler sets the function rendering the errors returned by
+// adapted handlers, replacing the default problem response.
+func ErrorHandler(h ErrorHandlerFunc) Option {
+	if h == nil {
+		panic("handler param cannot be nil")
+	}
+	return func(r *router) {
+		r.errorHandler = h
+	}
+}
+
+func notFound(w http.ResponseWriter, r *http.Request) {
+	p := response.NewProblem(http.StatusNotFound, "the requested resource could not be found")
+	p.Instance = r.URL.Path
+	p.Write(w)
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	p := response.NewProblem(http.StatusMethodNotAllowed, fmt.Sprintf("the %s method is not supported for this resource", r.Method))
+	p.Instance = r.URL.Path
+	p.Write(w)
+}
+
+// joinPath appends the route path to the group prefix,
+// the root path of a group is the prefix itself.
+func joinPath(prefix, path string) string {
+	if prefix != "" && path == "/" {
+		return prefix
+	}
+	return prefix + path
+}
 
-	// Register all routes added by the user.
+// register adds the routes of the router and its groups to the mux.
+// Prefix and middlewares are the ones inherited from the parent groups,
+// heads are the full paths with an explicit HEAD route in any group.
+func (r *router) register(mux *httprouter.Router, prefix string, middlewares []Middleware, heads map[string]bool) {
+	prefix += r.prefix
+	middlewares = slices.Concat(middlewares, r.middlewares)
+
+	// Router middlewares run before the route ones,
+	// no matter when they were added.
 	for _, route := range r.routes {
-		mux.HandlerFunc(route.method, route.path, route.handler)
+		path := joinPath(prefix, route.path)
+		h := chain(route.handler, slices.Concat(middlewares, route.middlewares)...)
+		h = withRoute(path, h)
+		mux.Handler(route.method, path, h)
+
+		// GET routes also answer HEAD requests,
+		// unless a HEAD handler was added for the same path.
+		if route.method == http.MethodGet && !heads[path] {
+			mux.Handler(http.MethodHead, path, head(h))
+		}
 	}
 
+	for _, group := range r.groups {
+		group.register(mux, prefix, middlewares, heads)
+	}
+}
+
+// collectHeads adds the full paths of the HEAD routes
+// of the router and its groups to heads.
+func (r *router) collectHeads(prefix string, heads map[string]bool) {
+	prefix += r.prefix
+	for _, route := range r.routes {
+		if route.method == http.MethodHead {
+			heads[joinPath(prefix, route.path)] = true
+		}
+	}
+	for _, group := range r.groups {
+		group.collectHeads(prefix, heads)
+	}
+}
+
+// withRoute stores the route pattern in the request and adds it
+// to the request logger, so it can be read by middlewares and logged.
+func withRoute(pattern string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = request.SetRoute(r, pattern)
+		r = request.WithLogger(r, request.Logger(r).With(slog.String("route", pattern)))
+		h.ServeHTTP(w, r)
+	})
+}
+
+// headWriter discards the body written by a GET handler,
+// keeping the headers and the status code.
+type headWriter struct {
+	http.ResponseWriter
+}
+
+func (w headWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func head(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(headWriter{w}, r)
+	})
+}
+
+func (r *router) Mux() http.Handler {
+	mux := httprouter.New()
+	mux.NotFound = r.notFound
+	mux.MethodNotAllowed = r.methodNotAllowed
+	mux.PanicHandler = r.panicHandler
+
+	// Register all routes added by the user,
+	// groups are flattened into the same mux.
+	heads := make(map[string]bool)
+	r.collectHeads("", heads)
+	r.register(mux, "", nil, heads)
+
 	return mux
 }
 
+// Route returns a sub-router whose routes share the prefix and
+// inherit the middlewares of r, extended with its own ones.
+func (r *router) Route(prefix string) Router {
+	group := build()
+	group.exposeErrors = r.exposeErrors
+	group.errorHandler = r.errorHandler
+	group.prefix = strings.TrimSuffix(BuildPath(strings.Trim(prefix, "/")), "/")
+	r.groups = append(r.groups, group)
+	return group
+}
+
+// Group calls fn with a sub-router for the given prefix.
+func (r *router) Group(prefix string, fn func(Router)) {
+	if fn == nil {
+		panic("fn param cannot be nil")
+	}
+	fn(r.Route(prefix))
+}
+
 func panicNilHandler(h http.HandlerFunc) {
 	if h == nil {
 		panic("handler param cannot be nil")
 	}
 }
 
-func (r *router) Get(path string, handler http.HandlerFunc) {
-	panicNilHandler(handler)
-	r.routes = append(r.routes, buildRoute(http.MethodGet, path, handler))
+func panicNilMiddleware(middlewares []Middleware) {
+	for _, m := range middlewares {
+		if m == nil {
+			panic("middleware param cannot be nil")
+		}
+	}
 }
 
-func (r *router) Post(path string, handler http.HandlerFunc) {
-	panicNilHandler(handler)
-	r.routes = append(r.routes, buildRoute(http.MethodPost, path, handler))
+func (r *router) Use(middlewares ...Middleware) {
+	panicNilMiddleware(middlewares)
+	r.middlewares = append(r.middlewares, middlewares...)
 }
 
-func (r *router) Put(path string, handler http.HandlerFunc) {
+func (r *router) add(method, path string, handler http.HandlerFunc, middlewares []Middleware) {
 	panicNilHandler(handler)
-	r.routes = append(r.routes, buildRoute(http.MethodPut, path, handler))
+	panicNilMiddleware(middlewares)
+	r.routes = append(r.routes, buildRoute(method, path, handler, middlewares))
 }
 
-func (r *router) Patch(path string, handler http.HandlerFunc) {
-	panicNilHandler(handler)
-	r.routes = append(r.routes, buildRoute(http.MethodPatch, path, handler))
+func (r *router) Get(path string, handler http.HandlerFunc, middlewares ...Middleware) {
+	r.add(http.MethodGet, path, handler, middlewares)
 }
 
-func (r *router) Delete(path string, handler http.HandlerFunc) {
-	panicNilHandler(handler)
-	r.routes = append(r.routes, buildRoute(http.MethodDelete, path, handler))
+func (r *router) Post(path string, handler http.HandlerFunc, middlewares ...Middleware) {
+	r.add(http.MethodPost, path, handler, middlewares)
+}
+
+func (r *router) Put(path string, handler http.HandlerFunc, middlewares ...Middleware) {
+	r.add(http.MethodPut, path, handler, middlewares)
+}
+
+func (r *router) Patch(path string, handler http.HandlerFunc, middlewares ...Middleware) {
+	r.add(http.MethodPatch, path, handler, middlewares)
+}
+
+func (r *router) Delete(path string, handler http.HandlerFunc, middlewares ...Middleware) {
+	r.add(http.MethodDelete, path, handler, middlewares)
+}
+
+func (r *router) Head(path string, handler http.HandlerFunc, middlewares ...Middleware) {
+	r.add(http.MethodHead, path, handler, middlewares)
+}
+
+func (r *router) Options(path string, handler http.HandlerFunc, middlewares ...Middleware) {
+	r.add(http.MethodOptions, path, handler, middlewares)
+}
+
+// Handle adds a route for any HTTP method, including non standard ones.
+func (r *router) Handle(method, path string, handler http.HandlerFunc, middlewares ...Middleware) {
+	if method == "" {
+		panic("method param cannot be empty")
+	}
+	r.add(method, path, handler, middlewares)
+}
+
+// handleError logs err with its internal cause and sends it
+// to the client as a problem, see response.AppError.
+func (r *router) handleError(w http.ResponseWriter, req *http.Request, err error) {
+	status := http.StatusInternalServerError
+	var appErr *response.AppError
+	if errors.As(err, &appErr) {
+		status = appErr.Status
+	}
+
+	level := slog.LevelWarn
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	request.Logger(req).Log(req.Context(), level, "handler error", "status", status, "error", err)
+
+	response.Fail(w, req, err, r.exposeErrors)
+}
+
+// Adapt turns a handler returning an error into an http.HandlerFunc.
+// Returned errors are sent through the router error handler.
+func (r *router) Adapt(handler HandlerFunc) http.HandlerFunc {
+	if handler == nil {
+		panic("handler param cannot be nil")
+	}
+	return func(w http.ResponseWriter, req *http.Request) {
+		err := handler(w, req)
+		if err == nil {
+			return
+		}
+
+		if r.errorHandler != nil {
+			r.errorHandler(w, req, err)
+			return
+		}
+		r.handleError(w, req, err)
+	}
+}
+
+// HandleErr adds a route for a handler returning an error,
+// it is the same as calling Handle with the adapted handler.
+func (r *router) HandleErr(method, path string, handler HandlerFunc, middlewares ...Middleware) {
+	r.Handle(method, path, r.Adapt(handler), middlewares...)
 }
 
 func BuildPath(path string) string {
@@ -78,10 +361,18 @@ func BuildPath(path string) string {
 
 func build() *router {
 	return &router{
-		routes: make([]*route, 0),
+		routes:           make([]*route, 0),
+		middlewares:      make([]Middleware, 0),
+		groups:           make([]*router, 0),
+		notFound:         http.HandlerFunc(notFound),
+		methodNotAllowed: http.HandlerFunc(methodNotAllowed),
 	}
 }
 
-func New() Router {
-	return build()
+func New(options ...Option) Router {
+	r := build()
+	for _, option := range options {
+		option(r)
+	}
+	return r
 }
@@ -1,17 +1,24 @@
 package router
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/nukiro/modular/internal/tests"
+	"github.com/nukiro/modular/request"
+	"github.com/nukiro/modular/response"
 )
 
 func TestMux(t *testing.T) {
 	rt := router{
 		routes: []*route{
-			{http.MethodGet, "/", func(w http.ResponseWriter, r *http.Request) {}},
+			{method: http.MethodGet, path: "/", handler: func(w http.ResponseWriter, r *http.Request) {}},
 		},
 	}
 
@@ -31,6 +38,318 @@ func TestMux(t *testing.T) {
 
 }
 
+func TestRoutePattern(t *testing.T) {
+	var got string
+
+	router := build()
+	router.Group("v1", func(v1 Router) {
+		v1.Get("articles/:id", func(w http.ResponseWriter, r *http.Request) {
+			got = request.Route(r)
+		})
+	})
+
+	w := httptest.NewRecorder()
+	router.Mux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/articles/1", nil))
+
+	if got != "/v1/articles/:id" {
+		t.Errorf("got route %q, but want %q", got, "/v1/articles/:id")
+	}
+}
+
+func TestUse(t *testing.T) {
+	t.Run("middlewares order", func(t *testing.T) {
+		var calls []string
+		middleware := func(name string) Middleware {
+			return func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					calls = append(calls, name)
+					next.ServeHTTP(w, r)
+				})
+			}
+		}
+
+		router := build()
+		router.Use(middleware("first"))
+		router.Get("articles", func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, "handler")
+		}, middleware("route"))
+		// Global middlewares apply to routes registered before them.
+		router.Use(middleware("second"))
+
+		w := httptest.NewRecorder()
+		router.Mux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles", nil))
+
+		want := []string{"first", "second", "route", "handler"}
+		if fmt.Sprint(calls) != fmt.Sprint(want) {
+			t.Errorf("got calls %v, but want %v", calls, want)
+		}
+	})
+
+	t.Run("nil middleware", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "Use", "middleware")
+		}()
+
+		router := build()
+		router.Use(nil)
+	})
+
+	t.Run("nil route middleware", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "Get", "middleware")
+		}()
+
+		router := build()
+		router.Get("", func(w http.ResponseWriter, r *http.Request) {}, nil)
+	})
+}
+
+func TestGroup(t *testing.T) {
+	t.Run("shared prefix and middlewares", func(t *testing.T) {
+		var calls []string
+		middleware := func(name string) Middleware {
+			return func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					calls = append(calls, name)
+					next.ServeHTTP(w, r)
+				})
+			}
+		}
+		handler := func(w http.ResponseWriter, r *http.Request) {}
+
+		router := build()
+		router.Use(middleware("root"))
+		router.Group("v1", func(v1 Router) {
+			v1.Use(middleware("v1"))
+			v1.Get("", handler)
+			v1.Get("users", handler)
+
+			orders := v1.Route("/orders/")
+			orders.Use(middleware("orders"))
+			orders.Get(":id", handler)
+		})
+
+		mux := router.Mux()
+
+		tests := []struct {
+			path  string
+			calls []string
+		}{
+			{"/v1", []string{"root", "v1"}},
+			{"/v1/users", []string{"root", "v1"}},
+			{"/v1/orders/1", []string{"root", "v1", "orders"}},
+		}
+		for _, tt := range tests {
+			calls = nil
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != 200 {
+				t.Errorf("%s got status %d, but want %d", tt.path, w.Code, 200)
+			}
+			if fmt.Sprint(calls) != fmt.Sprint(tt.calls) {
+				t.Errorf("%s got calls %v, but want %v", tt.path, calls, tt.calls)
+			}
+		}
+	})
+
+	t.Run("nil group function", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "Group", "fn")
+		}()
+
+		router := build()
+		router.Group("v1", nil)
+	})
+}
+
+func TestNotFound(t *testing.T) {
+	t.Run("default handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		New().Mux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+		assertProblem(t, w, http.StatusNotFound)
+	})
+
+	t.Run("custom handler", func(t *testing.T) {
+		router := New(NotFound(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})))
+
+		w := httptest.NewRecorder()
+		router.Mux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+		if w.Code != http.StatusTeapot {
+			t.Errorf("got status %d, but want %d", w.Code, http.StatusTeapot)
+		}
+	})
+
+	t.Run("nil handler", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "NotFound", "handler")
+		}()
+
+		NotFound(nil)
+	})
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	t.Run("default handler", func(t *testing.T) {
+		router := New()
+		router.Get("articles", func(w http.ResponseWriter, r *http.Request) {})
+		router.Post("articles", func(w http.ResponseWriter, r *http.Request) {})
+
+		w := httptest.NewRecorder()
+		router.Mux().ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/articles", nil))
+
+		assertProblem(t, w, http.StatusMethodNotAllowed)
+		if got := w.Header().Get("Allow"); got != "GET, HEAD, OPTIONS, POST" {
+			t.Errorf("got allow header %q, but want %q", got, "GET, HEAD, OPTIONS, POST")
+		}
+	})
+
+	t.Run("nil handler", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "MethodNotAllowed", "handler")
+		}()
+
+		MethodNotAllowed(nil)
+	})
+}
+
+func TestPanicHandler(t *testing.T) {
+	t.Run("custom handler", func(t *testing.T) {
+		router := New(PanicHandler(func(w http.ResponseWriter, r *http.Request, err any) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		router.Get("articles", func(w http.ResponseWriter, r *http.Request) {
+			panic("an error")
+		})
+
+		w := httptest.NewRecorder()
+		router.Mux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles", nil))
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("got status %d, but want %d", w.Code, http.StatusServiceUnavailable)
+		}
+	})
+
+	t.Run("nil handler", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "PanicHandler", "handler")
+		}()
+
+		PanicHandler(nil)
+	})
+}
+
+func TestAdapt(t *testing.T) {
+	errs := []struct {
+		name   string
+		expose bool
+		err    error
+		code   int
+		cause  bool
+	}{
+		{"without error", false, nil, http.StatusOK, false},
+		{"application error", false, response.NewError(http.StatusNotFound, "not_found", "not found", errors.New("no rows")), http.StatusNotFound, false},
+		{"internal error", false, errors.New("connection refused"), http.StatusInternalServerError, false},
+		{"exposed internal error", true, errors.New("connection refused"), http.StatusInternalServerError, true},
+	}
+
+	for _, tt := range errs {
+		t.Run(tt.name, func(t *testing.T) {
+			router := New(ExposeErrors(tt.expose))
+			// Groups inherit the router options.
+			router.Group("v1", func(v1 Router) {
+				v1.Get("articles", v1.Adapt(func(w http.ResponseWriter, r *http.Request) error {
+					return tt.err
+				}))
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/v1/articles", nil)
+			r = request.WithLogger(r, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			router.Mux().ServeHTTP(w, r)
+
+			if tt.err == nil {
+				if w.Code != http.StatusOK {
+					t.Errorf("got status %d, but want %d", w.Code, http.StatusOK)
+				}
+				return
+			}
+
+			body := assertProblem(t, w, tt.code)
+			if _, ok := body["cause"]; ok != tt.cause {
+				t.Errorf("got cause in body %t, but want %t", ok, tt.cause)
+			}
+		})
+	}
+
+	t.Run("nil handler", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "Adapt", "handler")
+		}()
+
+		New().Adapt(nil)
+	})
+}
+
+func TestErrorHandler(t *testing.T) {
+	t.Run("custom handler", func(t *testing.T) {
+		var got error
+		router := New(ErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+			got = err
+			w.WriteHeader(http.StatusTeapot)
+		}))
+
+		want := errors.New("an error")
+		router.HandleErr(http.MethodGet, "articles", func(w http.ResponseWriter, r *http.Request) error {
+			return want
+		})
+
+		w := httptest.NewRecorder()
+		router.Mux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles", nil))
+
+		if got != want {
+			t.Errorf("got error %v, but want %v", got, want)
+		}
+		if w.Code != http.StatusTeapot {
+			t.Errorf("got status %d, but want %d", w.Code, http.StatusTeapot)
+		}
+	})
+
+	t.Run("nil handler", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "ErrorHandler", "handler")
+		}()
+
+		ErrorHandler(nil)
+	})
+}
+
+func TestHandleErr(t *testing.T) {
+	t.Run("new route", func(t *testing.T) {
+		router := build()
+
+		router.HandleErr(http.MethodPost, "articles", func(w http.ResponseWriter, r *http.Request) error {
+			return nil
+		})
+
+		assertRoutes(t, router.routes, "POST", "/articles")
+	})
+
+	t.Run("nil route handler", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "HandleErr", "handler")
+		}()
+
+		router := build()
+		router.HandleErr(http.MethodPost, "", nil)
+	})
+}
+
 func TestFullPath(t *testing.T) {
 	tests := []struct {
 		path string
@@ -157,6 +476,158 @@ func TestDelete(t *testing.T) {
 	})
 }
 
+func TestHead(t *testing.T) {
+	t.Run("new route", func(t *testing.T) {
+		router := build()
+
+		path := "articles"
+		handler := func(w http.ResponseWriter, r *http.Request) {}
+		router.Head(path, handler)
+
+		assertRoutes(t, router.routes, "HEAD", "/articles")
+	})
+
+	t.Run("nil route handler", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "Head", "handler")
+		}()
+
+		router := build()
+		router.Head("", nil)
+	})
+
+	t.Run("fallback to get handler", func(t *testing.T) {
+		router := build()
+		router.Get("articles", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Test", "get")
+			w.Write([]byte("body"))
+		})
+
+		w := httptest.NewRecorder()
+		router.Mux().ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/articles", nil))
+
+		if w.Code != 200 {
+			t.Errorf("got status %d, but want %d", w.Code, 200)
+		}
+		if w.Header().Get("X-Test") != "get" {
+			t.Errorf("get handler was not called")
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("got body %q, but want an empty body", w.Body.String())
+		}
+	})
+
+	t.Run("explicit head handler", func(t *testing.T) {
+		router := build()
+		router.Get("articles", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Test", "get")
+		})
+		router.Head("articles", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Test", "head")
+		})
+
+		w := httptest.NewRecorder()
+		router.Mux().ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/articles", nil))
+
+		if w.Header().Get("X-Test") != "head" {
+			t.Errorf("head handler was not called")
+		}
+	})
+
+	t.Run("explicit head handler in a group", func(t *testing.T) {
+		router := build()
+		router.Get("v1/ping", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Test", "get")
+		})
+		router.Group("v1", func(r Router) {
+			r.Head("ping", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Test", "head")
+			})
+		})
+
+		w := httptest.NewRecorder()
+		router.Mux().ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/v1/ping", nil))
+
+		if w.Header().Get("X-Test") != "head" {
+			t.Errorf("head handler was not called")
+		}
+	})
+}
+
+func TestOptions(t *testing.T) {
+	t.Run("new route", func(t *testing.T) {
+		router := build()
+
+		path := "articles"
+		handler := func(w http.ResponseWriter, r *http.Request) {}
+		router.Options(path, handler)
+
+		assertRoutes(t, router.routes, "OPTIONS", "/articles")
+	})
+
+	t.Run("nil route handler", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "Options", "handler")
+		}()
+
+		router := build()
+		router.Options("", nil)
+	})
+}
+
+func TestHandle(t *testing.T) {
+	t.Run("new route", func(t *testing.T) {
+		router := build()
+
+		path := "articles"
+		handler := func(w http.ResponseWriter, r *http.Request) {}
+		router.Handle("PROPFIND", path, handler)
+
+		assertRoutes(t, router.routes, "PROPFIND", "/articles")
+	})
+
+	t.Run("nil route handler", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "Handle", "handler")
+		}()
+
+		router := build()
+		router.Handle("PROPFIND", "", nil)
+	})
+
+	t.Run("empty method", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicEmptyParam(t, recover(), "Handle", "method")
+		}()
+
+		router := build()
+		router.Handle("", "", func(w http.ResponseWriter, r *http.Request) {})
+	})
+}
+
+func assertProblem(t testing.TB, w *httptest.ResponseRecorder, code int) map[string]any {
+	t.Helper()
+
+	if w.Code != code {
+		t.Errorf("got status %d, but want %d", w.Code, code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("got content type %q, but want %q", got, "application/problem+json")
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["status"] != float64(code) {
+		t.Errorf("got body status %v, but want %d", body["status"], code)
+	}
+	if body["detail"] == nil {
+		t.Errorf("response body does not contain a detail message")
+	}
+	return body
+}
+
 func assertRoutes(t testing.TB, routes []*route, method, path string) {
 	t.Helper()
 
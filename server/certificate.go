@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type certificate struct {
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	certFile string
+	keyFile  string
+	modified time.Time
+	logger   *slog.Logger
+}
+
+func newCertificate(certFile, keyFile string, logger *slog.Logger) (*certificate, error) {
+	c := &certificate{certFile: certFile, keyFile: keyFile, logger: logger}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// lastModified returns the most recent modification time
+// of the certificate and the private key files.
+func (c *certificate) lastModified() (time.Time, error) {
+	cert, err := os.Stat(c.certFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	key, err := os.Stat(c.keyFile)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if key.ModTime().After(cert.ModTime()) {
+		return key.ModTime(), nil
+	}
+	return cert.ModTime(), nil
+}
+
+func (c *certificate) load() error {
+	modified, err := c.lastModified()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cert = &cert
+	c.modified = modified
+	return nil
+}
+
+// reload loads the key pair again. If the new pair is not valid,
+// the error is logged and the previous certificate keeps being served.
+func (c *certificate) reload(reason string) {
+	if err := c.load(); err != nil {
+		c.logger.Error("certificate reload failed", "reason", reason, "cert", c.certFile, "key", c.keyFile, "error", err)
+		return
+	}
+	c.logger.Info("certificate reloaded", "reason", reason, "cert", c.certFile, "key", c.keyFile)
+}
+
+// changed reports whether the files were modified after the last load.
+func (c *certificate) changed() bool {
+	modified, err := c.lastModified()
+	if err != nil {
+		// Files can be missing for a moment while they are rotated,
+		// they will be checked again on the next tick.
+		return false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return modified.After(c.modified)
+}
+
+func (c *certificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cert, nil
+}
+
+// watch reloads the certificate when the process receives a SIGHUP and,
+// if interval is not zero, when the files change on disk.
+// It stops when the context is done.
+func (c *certificate) watch(ctx context.Context, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			c.reload("signal")
+		case <-tick:
+			if c.changed() {
+				c.reload("modified")
+			}
+		}
+	}
+}
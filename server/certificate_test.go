@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCertificate(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("load a valid key pair", func(t *testing.T) {
+		certFile, keyFile := writeKeyPair(t, t.TempDir(), "first")
+
+		c, err := newCertificate(certFile, keyFile, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertCertificate(t, c, "first")
+	})
+
+	t.Run("missing files", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := newCertificate(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), logger)
+		if err == nil {
+			t.Errorf("newCertificate did not return an error")
+		}
+	})
+
+	t.Run("reload a new key pair", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile, keyFile := writeKeyPair(t, dir, "first")
+
+		c, err := newCertificate(certFile, keyFile, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		writeKeyPair(t, dir, "second")
+		touch(t, certFile, keyFile)
+
+		if !c.changed() {
+			t.Errorf("certificate files were not detected as changed")
+		}
+
+		c.reload("modified")
+		assertCertificate(t, c, "second")
+
+		if c.changed() {
+			t.Errorf("certificate files were detected as changed after reload")
+		}
+	})
+
+	t.Run("keep serving the old key pair when the new one is invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile, keyFile := writeKeyPair(t, dir, "first")
+
+		c, err := newCertificate(certFile, keyFile, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(certFile, []byte("invalid"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		c.reload("signal")
+		assertCertificate(t, c, "first")
+	})
+}
+
+func TestWatch(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("reload on SIGHUP", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile, keyFile := writeKeyPair(t, dir, "first")
+
+		c, err := newCertificate(certFile, keyFile, logger)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Keep the signal from stopping the test binary
+		// until the watcher has registered its own channel.
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		defer signal.Stop(hup)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go c.watch(ctx, 0)
+
+		writeKeyPair(t, dir, "second")
+		eventually(t, func() bool {
+			syscall.Kill(os.Getpid(), syscall.SIGHUP)
+			return commonName(t, c) == "second"
+		})
+	})
+
+	t.Run("serve the rotated files after a tick", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile, keyFile := writeKeyPair(t, dir, "first")
+
+		config := Create()
+		config.Host = "127.0.0.1"
+		config.Port = freePort(t)
+		config.TLS = true
+		config.CertFile = certFile
+		config.KeyFile = keyFile
+		config.CertReloadInterval = 10 * time.Millisecond
+
+		srv := new(&config)
+		srv.Logger(logger)
+		srv.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		done := make(chan error, 1)
+		go func() { done <- srv.listen() }()
+		defer func() {
+			srv.shutdown(context.Background())
+			<-done
+		}()
+
+		if cert := dialTLS(t, srv.address()); cert.Subject.CommonName != "first" {
+			t.Fatalf("got certificate %q, but want %q", cert.Subject.CommonName, "first")
+		}
+
+		writeKeyPair(t, dir, "second")
+		touch(t, certFile, keyFile)
+		eventually(t, func() bool {
+			return dialTLS(t, srv.address()).Subject.CommonName == "second"
+		})
+	})
+}
+
+// eventually fails the test if cond is not true within a second.
+func eventually(t testing.TB, cond func() bool) {
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("condition was not met in time")
+}
+
+func commonName(t testing.TB, c *certificate) string {
+	t.Helper()
+
+	cert, err := c.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func assertCertificate(t testing.TB, c *certificate, name string) {
+	t.Helper()
+
+	if got := commonName(t, c); got != name {
+		t.Errorf("got certificate %q, but want %q", got, name)
+	}
+}
+
+// touch moves the modification time of the files forward, so the
+// change is detected even on file systems with coarse timestamps.
+func touch(t testing.TB, files ...string) {
+	t.Helper()
+
+	future := time.Now().Add(time.Minute)
+	for _, f := range files {
+		if err := os.Chtimes(f, future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/nukiro/modular/request"
+)
+
+// responseWriter records the status code and the number
+// of bytes written to the client.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = code
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+		f.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to reach the original writer.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// level returns the log level for the response status class.
+func level(status int) slog.Level {
+	switch {
+	case status >= 500:
+		return slog.LevelError
+	case status >= 400:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// logRequests writes one log record for each request once the
+// response has been sent or the handler has aborted it.
+func (s *server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+			r = request.WithRoute(r)
+			// Handlers log through the server logger with
+			// the request attributes already set.
+			r = request.WithLogger(r, s.logger.With(
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("request_id", request.ID(r)),
+			))
+
+			// The record is written from a deferred call so requests aborted
+			// by a panic, such as http.ErrAbortHandler once the response has
+			// started, are logged too before the panic goes on.
+			defer func() {
+				err := recover()
+
+				attrs := []slog.Attr{
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.String("route", request.Route(r)),
+					slog.Int("status", rw.status),
+					slog.Int("bytes", rw.bytes),
+					slog.Duration("latency", time.Since(start)),
+					slog.String("remote_addr", r.RemoteAddr),
+					slog.String("user_agent", r.UserAgent()),
+					slog.String("request_id", request.ID(r)),
+				}
+				lvl := level(rw.status)
+				if err != nil {
+					attrs = append(attrs, slog.Bool("aborted", true))
+					lvl = slog.LevelError
+				}
+				s.logger.LogAttrs(r.Context(), lvl, "request", attrs...)
+
+				if err != nil {
+					panic(err)
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+}
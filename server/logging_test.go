@@ -0,0 +1,167 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nukiro/modular/internal/tests"
+	"github.com/nukiro/modular/request"
+)
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		status int
+		want   slog.Level
+	}{
+		{http.StatusOK, slog.LevelInfo},
+		{http.StatusMovedPermanently, slog.LevelInfo},
+		{http.StatusNotFound, slog.LevelWarn},
+		{http.StatusInternalServerError, slog.LevelError},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			if got := level(tt.status); got != tt.want {
+				t.Errorf("got level %s, but want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogRequests(t *testing.T) {
+	var buf bytes.Buffer
+	srv := new(nil)
+	srv.Logger(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	h := srv.logRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request.SetRoute(r, "/articles/:id")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("not found"))
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	r.Header.Set("User-Agent", "test")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"level":      "WARN",
+		"msg":        "request",
+		"method":     "GET",
+		"path":       "/articles/1",
+		"route":      "/articles/:id",
+		"status":     float64(404),
+		"bytes":      float64(9),
+		"user_agent": "test",
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("got %s %v, but want %v", key, record[key], value)
+		}
+	}
+	if _, ok := record["latency"]; !ok {
+		t.Errorf("record does not contain latency")
+	}
+}
+
+func TestLogAbortedRequests(t *testing.T) {
+	var buf bytes.Buffer
+	srv := new(nil)
+	srv.Logger(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	h := srv.requestID(srv.logRequests(srv.recoverPanic(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [`))
+		panic("an error")
+	}))))
+
+	func() {
+		defer func() {
+			tests.AssertPanic(t, recover(), "logRequests", http.ErrAbortHandler.Error())
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/articles", nil))
+	}()
+
+	// Find the access log record among the panic ones.
+	var record map[string]any
+	dec := json.NewDecoder(&buf)
+	for record["msg"] != "request" {
+		record = nil
+		if err := dec.Decode(&record); err != nil {
+			t.Fatalf("access log record was not written: %v", err)
+		}
+	}
+
+	want := map[string]any{
+		"level":   "ERROR",
+		"path":    "/articles",
+		"status":  float64(200),
+		"bytes":   float64(10),
+		"aborted": true,
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("got %s %v, but want %v", key, record[key], value)
+		}
+	}
+	if record["request_id"] == "" || record["request_id"] == nil {
+		t.Errorf("record does not contain the request id")
+	}
+}
+
+func TestResponseWriter(t *testing.T) {
+	w := httptest.NewRecorder()
+	rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+	rw.WriteHeader(http.StatusCreated)
+	rw.WriteHeader(http.StatusInternalServerError)
+	rw.Write([]byte("created"))
+
+	if rw.status != http.StatusCreated {
+		t.Errorf("got status %d, but want %d", rw.status, http.StatusCreated)
+	}
+	if w.Code != http.StatusCreated {
+		t.Errorf("got recorded status %d, but want %d", w.Code, http.StatusCreated)
+	}
+	if rw.bytes != 7 {
+		t.Errorf("got %d bytes, but want %d", rw.bytes, 7)
+	}
+}
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	srv := new(nil)
+	srv.Logger(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	h := srv.logRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request.Logger(r).Info("handler")
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/articles", nil)
+	h.ServeHTTP(httptest.NewRecorder(), request.WithID(r, "1234"))
+
+	// The handler record is written before the access log one.
+	var record map[string]any
+	if err := json.NewDecoder(&buf).Decode(&record); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"msg":        "handler",
+		"method":     "POST",
+		"path":       "/articles",
+		"request_id": "1234",
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("got %s %v, but want %v", key, record[key], value)
+		}
+	}
+}
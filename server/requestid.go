@@ -0,0 +1,57 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/nukiro/modular/request"
+)
+
+const defaultRequestIDHeader = "X-Request-ID"
+
+// validRequestID reports whether an incoming ID can be trusted,
+// IDs are logged so they must be short and printable.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func (s *server) requestIDHeader() string {
+	if s.config.RequestIDHeader == "" {
+		return defaultRequestIDHeader
+	}
+	return s.config.RequestIDHeader
+}
+
+// requestID reads the request ID from the incoming header or generates
+// a new one. It is stored in the request and sent back to the client.
+func (s *server) requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			header := s.requestIDHeader()
+
+			id := r.Header.Get(header)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+
+			w.Header().Set(header, id)
+			next.ServeHTTP(w, request.WithID(r, id))
+		})
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nukiro/modular/request"
+)
+
+func TestValidRequestID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"", false},
+		{"b7c6a0f2-1d3e-4c5b-9a8f-0e1d2c3b4a59", true},
+		{"with space", false},
+		{"line\nbreak", false},
+		{strings.Repeat("a", 129), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := validRequestID(tt.id); got != tt.want {
+				t.Errorf("got %t, but want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	t.Run("propagate incoming id", func(t *testing.T) {
+		srv := new(nil)
+
+		var got string
+		h := srv.requestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = request.ID(r)
+		}))
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Request-ID", "incoming")
+		h.ServeHTTP(w, r)
+
+		if got != "incoming" {
+			t.Errorf("got request id %q, but want %q", got, "incoming")
+		}
+		if w.Header().Get("X-Request-ID") != "incoming" {
+			t.Errorf("got response id %q, but want %q", w.Header().Get("X-Request-ID"), "incoming")
+		}
+	})
+
+	t.Run("generate a new id with a custom header", func(t *testing.T) {
+		config := Create()
+		config.RequestIDHeader = "X-Correlation-ID"
+		srv := new(&config)
+
+		var got string
+		h := srv.requestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = request.ID(r)
+		}))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if len(got) != 32 {
+			t.Errorf("got request id %q, but want a generated one", got)
+		}
+		if w.Header().Get("X-Correlation-ID") != got {
+			t.Errorf("got response id %q, but want %q", w.Header().Get("X-Correlation-ID"), got)
+		}
+	})
+}
@@ -2,15 +2,22 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime/debug"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
+
+	"github.com/nukiro/modular/request"
+	"github.com/nukiro/modular/response"
 )
 
 const (
@@ -29,6 +36,22 @@ type Configuration struct {
 	IdleTimeout  time.Duration
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	// Certificate and private key files used when TLS is enabled.
+	// Both can be left empty if TLSConfig already provides
+	// the certificates (Certificates or GetCertificate).
+	CertFile  string
+	KeyFile   string
+	TLSConfig *tls.Config
+	// When TLS is enabled and RedirectPort is not zero, a plain HTTP
+	// listener is started on that port redirecting every request to HTTPS.
+	RedirectPort int
+	// Certificate files are reloaded on SIGHUP and, when the interval
+	// is not zero, every time they change on disk.
+	CertReloadInterval time.Duration
+	// Header used to read and send the request ID, X-Request-ID by default.
+	RequestIDHeader string
+	// Defaults used by request.Read, handlers can override them on each call.
+	Body request.Options
 }
 
 // Default server configuration
@@ -40,42 +63,187 @@ var configuration *Configuration = &Configuration{
 	time.Minute,
 	5 * time.Second,
 	10 * time.Second,
+	"",
+	"",
+	nil,
+	0,
+	0,
+	"X-Request-ID",
+	request.DefaultOptions(),
 }
 
 type Server interface {
 	Run() error
 	Logger(*slog.Logger)
 	Handler(http.Handler)
+	OnPanic(PanicHook)
 }
 
 type server struct {
 	*http.Server
-	config *Configuration
-	logger *slog.Logger
+	config    *Configuration
+	logger    *slog.Logger
+	redirect  *http.Server
+	panicHook PanicHook
 }
 
 func (s *server) address() string {
 	return fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
 }
 
+// tlsConfig returns the TLS configuration used by the server.
+// A configuration provided by the user is cloned and only hardened
+// when it does not set a minimum version.
+func (s *server) tlsConfig() *tls.Config {
+	if s.config.TLSConfig != nil {
+		config := s.config.TLSConfig.Clone()
+		if config.MinVersion == 0 {
+			config.MinVersion = tls.VersionTLS12
+		}
+		return config
+	}
+
+	return &tls.Config{
+		MinVersion:       tls.VersionTLS12,
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+	}
+}
+
+// redirectHandler sends every request to the same host and
+// path using the HTTPS scheme and the server port.
+func (s *server) redirectHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if s.config.Port != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(s.config.Port))
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+	})
+}
+
+func (s *server) listen() error {
+	if !s.config.TLS {
+		return s.Server.ListenAndServe()
+	}
+
+	s.Server.TLSConfig = s.tlsConfig()
+
+	certFile, keyFile := s.config.CertFile, s.config.KeyFile
+	if certFile != "" && keyFile != "" && s.Server.TLSConfig.GetCertificate == nil {
+		cert, err := newCertificate(certFile, keyFile, s.logger)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go cert.watch(ctx, s.config.CertReloadInterval)
+
+		// Certificates are served by the reloader, so ListenAndServeTLS
+		// must not load them again from the files.
+		s.Server.TLSConfig.GetCertificate = cert.GetCertificate
+		certFile, keyFile = "", ""
+	}
+
+	if s.config.RedirectPort != 0 {
+		s.redirect = &http.Server{
+			Addr:         fmt.Sprintf("%s:%d", s.config.Host, s.config.RedirectPort),
+			Handler:      s.redirectHandler(),
+			IdleTimeout:  s.config.IdleTimeout,
+			ReadTimeout:  s.config.ReadTimeout,
+			WriteTimeout: s.config.WriteTimeout,
+			ErrorLog:     s.Server.ErrorLog,
+		}
+
+		go func() {
+			s.logger.Info("starting redirect server", "addr", s.redirect.Addr)
+			err := s.redirect.ListenAndServe()
+			if !errors.Is(err, http.ErrServerClosed) {
+				s.logger.Error("redirect server", "error", err)
+			}
+		}()
+	}
+
+	return s.Server.ListenAndServeTLS(certFile, keyFile)
+}
+
+func (s *server) shutdown(ctx context.Context) error {
+	if s.redirect != nil {
+		if err := s.redirect.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+	return s.Server.Shutdown(ctx)
+}
+
+// PanicHook is called with the recovered value and the stack trace
+// after a handler panics, so it can be reported to an error tracker.
+type PanicHook func(r *http.Request, err any, stack []byte)
+
 func (s *server) recoverPanic(next http.Handler) http.Handler {
 	return http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
+			// Keep track of the response status, a handler may
+			// panic after it has already written the headers.
+			rw, ok := w.(*responseWriter)
+			if !ok {
+				rw = &responseWriter{ResponseWriter: w, status: http.StatusOK}
+			}
+
 			// Call builtin recover function to check
 			// it there has been a panic or not.
 			defer func() {
 				// If recover is called outside the deferred
 				// function it will not stop a panicking sequence.
-				if err := recover(); err != nil {
-					// Close the connection works as a trigger for the Go's
-					// HTTP server to automatically close the current connection.
-					w.Header().Set("Connection", "close")
-					w.WriteHeader(500)
-					s.logger.Error("server recover panic", "error", fmt.Sprintf("%s", err))
-					s.logger.Info("response", "code", 500)
+				err := recover()
+				if err == nil {
+					return
+				}
+				// The handler aborted the response on purpose,
+				// the Go's HTTP server must handle it.
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+
+				stack := debug.Stack()
+				request.Logger(r).Error("server recover panic", "error", fmt.Sprint(err), "stack", string(stack))
+
+				if s.panicHook != nil {
+					s.panicHook(r, err, stack)
 				}
+
+				// Nothing else can be sent if the response has already started,
+				// abort it so the client does not take it as complete.
+				if rw.wroteHeader {
+					panic(http.ErrAbortHandler)
+				}
+
+				p := response.NewProblem(http.StatusInternalServerError, "the server encountered a problem and could not process your request")
+				p.Instance = r.URL.Path
+				if s.config.Environment == Development {
+					p.Extensions["panic"] = fmt.Sprint(err)
+					p.Extensions["stack"] = strings.Split(strings.TrimSpace(string(stack)), "\n")
+				}
+
+				// Close the connection works as a trigger for the Go's
+				// HTTP server to automatically close the current connection.
+				rw.Header().Set("Connection", "close")
+				p.Write(rw)
 			}()
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(rw, r)
+		})
+}
+
+// bodyOptions sets the server defaults used to read the request body.
+func (s *server) bodyOptions(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, request.WithOptions(r, s.config.Body))
 		})
 }
 
@@ -91,7 +259,14 @@ func (s *server) Handler(handler http.Handler) {
 	if handler == nil {
 		panic("handler param cannot be nil")
 	}
-	s.Server.Handler = s.recoverPanic(handler)
+	s.Server.Handler = s.requestID(s.logRequests(s.recoverPanic(s.bodyOptions(handler))))
+}
+
+func (s *server) OnPanic(hook PanicHook) {
+	if hook == nil {
+		panic("hook param cannot be nil")
+	}
+	s.panicHook = hook
 }
 
 func (s *server) defaultMux() http.Handler {
@@ -100,11 +275,9 @@ func (s *server) defaultMux() http.Handler {
 	// It will route all incoming request,
 	// no matter the verb or the path
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		s.logger.Info("request", "method", r.Method, "path", r.URL.Path)
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("OK\n"))
-		s.logger.Info("response", "code", http.StatusOK)
 	})
 
 	return mux
@@ -141,12 +314,12 @@ func (s *server) Run() error {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		shutdownError <- s.Server.Shutdown(ctx)
+		shutdownError <- s.shutdown(ctx)
 	}()
 
 	// Starting the server.
-	s.logger.Info("starting server", "addr", s.address(), "env", s.config.Environment)
-	err := s.Server.ListenAndServe()
+	s.logger.Info("starting server", "addr", s.address(), "env", s.config.Environment, "tls", s.config.TLS)
+	err := s.listen()
 	// Calling Shutdown() on our server will cause ListenAndServe()
 	// to immediately return a server closed error.
 	if !errors.Is(err, http.ErrServerClosed) {
@@ -1,14 +1,31 @@
 package server
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"math/big"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/nukiro/modular/internal/tests"
+	"github.com/nukiro/modular/request"
 )
 
 func TestAddress(t *testing.T) {
@@ -102,6 +119,342 @@ func TestHandler(t *testing.T) {
 	})
 }
 
+func TestTLSConfig(t *testing.T) {
+	t.Run("default configuration", func(t *testing.T) {
+		srv := new(nil)
+		config := srv.tlsConfig()
+
+		if config.MinVersion != tls.VersionTLS12 {
+			t.Errorf("got min version %x, but want %x", config.MinVersion, tls.VersionTLS12)
+		}
+		if len(config.CurvePreferences) == 0 {
+			t.Errorf("curve preferences were not set")
+		}
+	})
+
+	t.Run("user configuration", func(t *testing.T) {
+		config := Create()
+		config.TLSConfig = &tls.Config{ServerName: "example.com"}
+
+		srv := new(&config)
+		got := srv.tlsConfig()
+
+		if got == config.TLSConfig {
+			t.Errorf("user tls configuration was not cloned")
+		}
+		if got.ServerName != "example.com" {
+			t.Errorf("got server name %q, but want %q", got.ServerName, "example.com")
+		}
+		if got.MinVersion != tls.VersionTLS12 {
+			t.Errorf("got min version %x, but want %x", got.MinVersion, tls.VersionTLS12)
+		}
+	})
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		port   int
+		target string
+		want   string
+	}{
+		{443, "http://example.com:8080/articles?page=2", "https://example.com/articles?page=2"},
+		{8443, "http://example.com/articles", "https://example.com:8443/articles"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			config := Create()
+			config.Port = tt.port
+			srv := new(&config)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			srv.redirectHandler().ServeHTTP(w, r)
+
+			if w.Code != http.StatusMovedPermanently {
+				t.Errorf("got status %d, but want %d", w.Code, http.StatusMovedPermanently)
+			}
+			if got := w.Header().Get("Location"); got != tt.want {
+				t.Errorf("got location %q, but want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListenTLS(t *testing.T) {
+	certFile, keyFile := writeKeyPair(t, t.TempDir(), "first")
+
+	config := Create()
+	config.Host = "127.0.0.1"
+	config.Port = freePort(t)
+	config.TLS = true
+	config.CertFile = certFile
+	config.KeyFile = keyFile
+	config.RedirectPort = freePort(t)
+
+	srv := new(&config)
+	srv.Logger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	srv.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	done := make(chan error, 1)
+	go func() { done <- srv.listen() }()
+
+	cert := dialTLS(t, srv.address())
+	if cert.Subject.CommonName != "first" {
+		t.Errorf("got certificate %q, but want %q", cert.Subject.CommonName, "first")
+	}
+
+	// The redirect listener answers plain HTTP with the HTTPS location.
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	res, err := client.Get(fmt.Sprintf("http://127.0.0.1:%d/articles", config.RedirectPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	want := fmt.Sprintf("https://127.0.0.1:%d/articles", config.Port)
+	if got := res.Header.Get("Location"); res.StatusCode != http.StatusMovedPermanently || got != want {
+		t.Errorf("got status %d and location %q, but want a redirect to %q", res.StatusCode, got, want)
+	}
+
+	if err := srv.shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; !errors.Is(err, http.ErrServerClosed) {
+		t.Errorf("got error %v, but want %v", err, http.ErrServerClosed)
+	}
+	if _, err := net.Dial("tcp", srv.redirect.Addr); err == nil {
+		t.Errorf("redirect server was not shut down")
+	}
+}
+
+func TestRecoverPanic(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("json error body", func(t *testing.T) {
+		tests := []struct {
+			env   Environment
+			stack bool
+		}{
+			{Development, true},
+			{Production, false},
+		}
+
+		for _, tt := range tests {
+			t.Run(string(tt.env), func(t *testing.T) {
+				config := Create()
+				config.Environment = tt.env
+				srv := new(&config)
+				srv.Logger(logger)
+
+				h := srv.recoverPanic(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					panic("an error")
+				}))
+
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+				if w.Code != http.StatusInternalServerError {
+					t.Errorf("got status %d, but want %d", w.Code, http.StatusInternalServerError)
+				}
+				if w.Header().Get("Connection") != "close" {
+					t.Errorf("connection header was not set to close")
+				}
+
+				var body map[string]any
+				if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+					t.Fatal(err)
+				}
+				if body["detail"] == nil {
+					t.Errorf("response body does not contain a detail message")
+				}
+				if _, ok := body["stack"]; ok != tt.stack {
+					t.Errorf("got stack in body %t, but want %t", ok, tt.stack)
+				}
+			})
+		}
+	})
+
+	t.Run("headers already written", func(t *testing.T) {
+		writes := []struct {
+			name  string
+			write func(w http.ResponseWriter)
+		}{
+			{"status", func(w http.ResponseWriter) { w.WriteHeader(http.StatusAccepted) }},
+			{"partial body", func(w http.ResponseWriter) { w.Write([]byte(`{"data": [`)) }},
+		}
+
+		for _, tt := range writes {
+			t.Run(tt.name, func(t *testing.T) {
+				srv := new(nil)
+				srv.Logger(logger)
+
+				var hooked bool
+				srv.OnPanic(func(r *http.Request, err any, s []byte) {
+					hooked = true
+				})
+
+				h := srv.recoverPanic(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					tt.write(w)
+					panic("an error")
+				}))
+
+				w := httptest.NewRecorder()
+				defer func() {
+					tests.AssertPanic(t, recover(), "recoverPanic", http.ErrAbortHandler.Error())
+					if !hooked {
+						t.Errorf("panic hook was not called")
+					}
+					if w.Header().Get("Content-Type") != "" {
+						t.Errorf("got a problem written after the response started")
+					}
+				}()
+
+				h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			})
+		}
+	})
+
+	t.Run("abort handler", func(t *testing.T) {
+		srv := new(nil)
+		srv.Logger(logger)
+
+		h := srv.recoverPanic(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		defer func() {
+			tests.AssertPanic(t, recover(), "recoverPanic", http.ErrAbortHandler.Error())
+		}()
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	t.Run("panic hook", func(t *testing.T) {
+		srv := new(nil)
+		srv.Logger(logger)
+
+		var got any
+		var stack []byte
+		srv.OnPanic(func(r *http.Request, err any, s []byte) {
+			got, stack = err, s
+		})
+
+		h := srv.recoverPanic(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("an error")
+		}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if got != "an error" {
+			t.Errorf("got panic %v, but want %q", got, "an error")
+		}
+		if len(stack) == 0 {
+			t.Errorf("stack trace was not reported")
+		}
+	})
+
+	t.Run("nil panic hook", func(t *testing.T) {
+		defer func() {
+			tests.AssertPanicNilParam(t, recover(), "OnPanic", "hook")
+		}()
+
+		srv := New()
+		srv.OnPanic(nil)
+	})
+}
+
+func TestBodyOptions(t *testing.T) {
+	config := Create()
+	config.Body.AllowUnknownFields = true
+	srv := new(&config)
+
+	var got error
+	h := srv.bodyOptions(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var dst struct{}
+		got = request.Read(w, r, &dst)
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"title": "Go"}`))
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	if got != nil {
+		t.Errorf("got error %q, but want none", got)
+	}
+}
+
+// freePort returns a TCP port which is not in use on the loopback interface.
+func freePort(t testing.TB) int {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// dialTLS connects to addr, waiting for the server to start,
+// and returns the certificate it serves.
+func dialTLS(t testing.TB, addr string) *x509.Certificate {
+	t.Helper()
+
+	var err error
+	for i := 0; i < 100; i++ {
+		var conn *tls.Conn
+		conn, err = tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+		if err == nil {
+			defer conn.Close()
+			return conn.ConnectionState().PeerCertificates[0]
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal(err)
+	return nil
+}
+
+// writeKeyPair generates a self-signed certificate for the given
+// common name and writes it to cert.pem and key.pem in dir.
+func writeKeyPair(t testing.TB, dir, name string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return certFile, keyFile
+}
+
 func assertServer(t testing.TB, srv *server, c *Configuration) {
 	t.Helper()
 